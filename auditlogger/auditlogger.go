@@ -0,0 +1,272 @@
+// Package auditlogger contains a logger that writes entries to an
+// append-only, tamper-evident audit file, and the function to verify
+// such files
+package auditlogger
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/pkg/errors"
+)
+
+// List of all errors
+var (
+	ErrClosed = errors.New("audit logger is closed")
+)
+
+// we make sure AuditLogger implements EntryLogger
+var _ logger.EntryLogger = (*AuditLogger)(nil)
+
+// AuditLogger is a go-routine safe logger that appends entries to an
+// audit file, chaining each entry to the previous one using its hash.
+// If a key is provided, every record is also signed with HMAC-SHA256.
+// Entries are synced to the disk before LogEntry returns.
+//
+// An AuditLogger is usually added to the managers of the sensitive
+// parts of an application, like the "[auth]" or "[billing]" ones.
+// Use Verify to check the integrity of a file
+type AuditLogger struct {
+	mu       sync.Mutex
+	path     string
+	f        file
+	size     int64 // size of the valid records of the file
+	key      []byte
+	seq      uint64
+	prevHash string
+	closed   bool
+}
+
+// file is the part of *os.File used by AuditLogger
+type file interface {
+	io.Writer
+	Sync() error
+	Truncate(size int64) error
+	Close() error
+}
+
+// Open opens or creates the audit file at path, and returns a logger that
+// appends entries to it. The chain of an existing file is continued.
+// key is used to sign the records, and can be nil.
+//
+// A last line that doesn't end with a new line has been torn by a crash
+// during a write. Since it has never been acknowledged, it's removed from
+// the file. Returns a *VerifyError if the last record of the file is
+// invalid, or has not been signed with key
+func Open(path string, key []byte) (*AuditLogger, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600) //nolint:gosec // the path is provided by the user
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open the audit file")
+	}
+
+	l := &AuditLogger{
+		path: path,
+		f:    f,
+		key:  key,
+	}
+	if err := l.resume(f); err != nil {
+		f.Close() //nolint:errcheck
+		return nil, err
+	}
+	return l, nil
+}
+
+// resume reads the last record of the file, if any, to continue the
+// chain. A torn last line is removed
+func (l *AuditLogger) resume(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Wrap(err, "could not stat the audit file")
+	}
+
+	var last, prev []byte
+	var lastLine, prevLine int
+	var offset, lastStart int64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, maxRecordSize)
+	for line := 1; scanner.Scan(); line++ {
+		size := int64(len(scanner.Bytes()))
+		offset += size + 1
+		if size == 0 {
+			continue
+		}
+		prev, prevLine = last, lastLine
+		last, lastLine = append([]byte(nil), scanner.Bytes()...), line
+		lastStart = offset - size - 1
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "could not read the audit file")
+	}
+
+	// The scanner doesn't tell if the last line ended with a new line,
+	// but we counted one for every line
+	l.size = offset
+	if offset > info.Size() {
+		if err := f.Truncate(lastStart); err != nil {
+			return errors.Wrap(err, "could not remove the torn record of the audit file")
+		}
+		l.size = lastStart
+		last, lastLine = prev, prevLine
+	}
+	if last == nil {
+		return nil
+	}
+
+	fail := func(reason string) error {
+		return &VerifyError{Line: lastLine, Reason: reason}
+	}
+	rec := &record{}
+	entry := &auditEntry{}
+	if err := json.Unmarshal(last, rec); err != nil {
+		return fail("invalid record: " + err.Error())
+	}
+	if err := json.Unmarshal(rec.Entry, entry); err != nil {
+		return fail("invalid entry: " + err.Error())
+	}
+	if hashEntry(rec.Entry) != rec.Hash {
+		return fail("the entry has been modified")
+	}
+	if l.key != nil && !hmac.Equal([]byte(sign(l.key, rec.Hash)), []byte(rec.HMAC)) {
+		return fail("invalid HMAC")
+	}
+
+	l.seq = entry.Seq
+	l.prevHash = rec.Hash
+	return nil
+}
+
+// ID returns the logger's unique ID
+func (l *AuditLogger) ID() string {
+	return "audit-logger:" + l.path
+}
+
+// Close closes the audit file
+// the logger may not be reusable after being closed
+func (l *AuditLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	return l.f.Close()
+}
+
+// IsClosed returns wether the logger is closed or not
+func (l *AuditLogger) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// LogEntry appends an entry to the audit file, and waits for it to be
+// synced to the disk.
+// An entry that has been written but could not be synced is still part
+// of the chain
+func (l *AuditLogger) LogEntry(e *logger.Entry) error {
+	t := e.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+
+	entry, err := json.Marshal(&auditEntry{
+		Seq:      l.seq + 1,
+		Time:     t.UTC().Format(time.RFC3339Nano),
+		Level:    e.Level.String(),
+		Tag:      e.Tag,
+		Message:  e.Message,
+		Data:     auditData(e.Data()),
+		PrevHash: l.prevHash,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not encode the entry")
+	}
+
+	rec := &record{
+		Entry: entry,
+		Hash:  hashEntry(entry),
+	}
+	if l.key != nil {
+		rec.HMAC = sign(l.key, rec.Hash)
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return errors.Wrap(err, "could not encode the record")
+	}
+
+	line = append(line, '\n')
+	if _, err := l.f.Write(line); err != nil {
+		// The next records would be appended to a partial one
+		l.f.Truncate(l.size) //nolint:errcheck
+		return errors.Wrap(err, "could not write the record")
+	}
+
+	// The record is part of the file, even if it cannot be synced
+	l.size += int64(len(line))
+	l.seq++
+	l.prevHash = rec.Hash
+
+	if err := l.f.Sync(); err != nil {
+		return errors.Wrap(err, "could not sync the audit file")
+	}
+	return nil
+}
+
+// auditData returns a copy of the data of an entry that can be encoded
+// to JSON
+func auditData(data map[string]interface{}) map[string]interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+
+	res := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		// errors are usually encoded as empty objects
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (l *AuditLogger) Error(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelError)
+}
+
+// Info logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Println.
+func (l *AuditLogger) Info(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelInfo)
+}
+
+// Debug logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Println.
+func (l *AuditLogger) Debug(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelDebug)
+}
+
+// Log logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Println.
+func (l *AuditLogger) Log(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelDefault)
+}
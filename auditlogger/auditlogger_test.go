@@ -0,0 +1,237 @@
+package auditlogger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditFile creates an audit file containing the given messages, and
+// returns its lines
+func newAuditFile(t *testing.T, key []byte, msgs ...string) (path string, lines []string) {
+	dir, err := ioutil.TempDir("", "auditlogger")
+	require.NoError(t, err)
+	path = filepath.Join(dir, "audit.log")
+
+	l, err := Open(path, key)
+	require.NoError(t, err)
+	m := logger.NewManagerWithTag("[auth]")
+	require.NoError(t, m.Add(l))
+	for _, msg := range msgs {
+		m.Info(msg)
+	}
+	require.Empty(t, m.Close())
+
+	data, err := ioutil.ReadFile(path) //nolint:gosec
+	require.NoError(t, err)
+	return path, strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// verifyFile verifies the audit file at path, and returns the number of
+// records
+func verifyFile(t *testing.T, path string, key []byte) int {
+	f, err := os.Open(path) //nolint:gosec
+	require.NoError(t, err)
+	defer f.Close() //nolint:errcheck
+	res, err := Verify(f, key)
+	require.NoError(t, err)
+	return res.Records
+}
+
+// syncFailingFile is a file that cannot be synced
+type syncFailingFile struct {
+	*os.File
+}
+
+func (f *syncFailingFile) Sync() error {
+	return errors.New("sync failed")
+}
+
+func TestAuditLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a valid file", func(t *testing.T) {
+		t.Parallel()
+
+		path, lines := newAuditFile(t, nil, "user logged in", "user logged out")
+		defer os.RemoveAll(filepath.Dir(path)) //nolint:errcheck
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"tag":"[auth]"`)
+		assert.Contains(t, lines[0], `"message":"user logged in"`)
+		assert.Contains(t, lines[0], `"prev_hash":""`)
+
+		res, err := Verify(strings.NewReader(strings.Join(lines, "")), nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Records)
+		assert.NotEmpty(t, res.LastHash)
+	})
+
+	t.Run("the chain is continued when reopening a file", func(t *testing.T) {
+		t.Parallel()
+
+		path, _ := newAuditFile(t, nil, "first")
+		defer os.RemoveAll(filepath.Dir(path)) //nolint:errcheck
+
+		l, err := Open(path, nil)
+		require.NoError(t, err)
+		l.Info("second")
+		require.NoError(t, l.Close())
+		assert.True(t, l.IsClosed())
+		assert.Equal(t, ErrClosed, l.LogEntry(&logger.Entry{Message: "third"}))
+
+		f, err := os.Open(path) //nolint:gosec
+		require.NoError(t, err)
+		defer f.Close() //nolint:errcheck
+		res, err := Verify(f, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Records)
+	})
+
+	t.Run("a torn last record is removed", func(t *testing.T) {
+		t.Parallel()
+
+		path, _ := newAuditFile(t, nil, "first", "second")
+		defer os.RemoveAll(filepath.Dir(path)) //nolint:errcheck
+
+		// the process crashed while writing the third record
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600) //nolint:gosec
+		require.NoError(t, err)
+		_, err = f.WriteString(`{"entry":{"seq":3,"time":`)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		l, err := Open(path, nil)
+		require.NoError(t, err)
+		l.Info("third")
+		require.NoError(t, l.Close())
+		assert.Equal(t, 3, verifyFile(t, path, nil))
+	})
+
+	t.Run("an invalid last record is reported", func(t *testing.T) {
+		t.Parallel()
+
+		key := []byte("secret")
+		path, lines := newAuditFile(t, key, "first", "second")
+		defer os.RemoveAll(filepath.Dir(path)) //nolint:errcheck
+
+		_, err := Open(path, []byte("other key"))
+		require.IsType(t, &VerifyError{}, err)
+		assert.Equal(t, &VerifyError{Line: 2, Reason: "invalid HMAC"}, err)
+
+		tampered := lines[0] + strings.Replace(lines[1], "second", "2nd", 1) + "\n"
+		require.NoError(t, ioutil.WriteFile(path, []byte(tampered), 0600))
+		_, err = Open(path, key)
+		assert.Equal(t, &VerifyError{Line: 2, Reason: "the entry has been modified"}, err)
+	})
+
+	t.Run("records that could not be synced are part of the chain", func(t *testing.T) {
+		t.Parallel()
+
+		path, _ := newAuditFile(t, nil, "first")
+		defer os.RemoveAll(filepath.Dir(path)) //nolint:errcheck
+
+		l, err := Open(path, nil)
+		require.NoError(t, err)
+		f := l.f.(*os.File)
+		l.f = &syncFailingFile{File: f}
+		assert.Error(t, l.LogEntry(logger.NewEntry("second", logger.LevelInfo)))
+		l.f = f
+		require.NoError(t, l.LogEntry(logger.NewEntry("third", logger.LevelInfo)))
+		require.NoError(t, l.Close())
+		assert.Equal(t, 3, verifyFile(t, path, nil))
+	})
+
+	t.Run("error values are encoded", func(t *testing.T) {
+		t.Parallel()
+
+		path, _ := newAuditFile(t, nil)
+		defer os.RemoveAll(filepath.Dir(path)) //nolint:errcheck
+
+		l, err := Open(path, nil)
+		require.NoError(t, err)
+		require.NoError(t, l.LogEntry(&logger.Entry{
+			Message: "payment failed",
+			Fields:  map[string]interface{}{"error": os.ErrPermission},
+		}))
+		require.NoError(t, l.Close())
+
+		data, err := ioutil.ReadFile(path) //nolint:gosec
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"error":"permission denied"`)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("secret")
+	path, lines := newAuditFile(t, key, "a", "b", "c")
+	defer os.RemoveAll(filepath.Dir(path)) //nolint:errcheck
+	require.Len(t, lines, 3)
+
+	testCases := []struct {
+		description string
+		content     string
+		key         []byte
+		line        int
+	}{
+		{
+			description: "an edited entry",
+			content:     lines[0] + strings.Replace(lines[1], `"message":"b"`, `"message":"x"`, 1) + lines[2],
+			key:         key,
+			line:        2,
+		},
+		{
+			description: "a deleted entry",
+			content:     lines[0] + lines[2],
+			key:         key,
+			line:        2,
+		},
+		{
+			description: "reordered entries",
+			content:     lines[1] + lines[0] + lines[2],
+			key:         key,
+			line:        1,
+		},
+		{
+			description: "a wrong key",
+			content:     strings.Join(lines, ""),
+			key:         []byte("not the secret"),
+			line:        1,
+		},
+		{
+			description: "an invalid record",
+			content:     lines[0] + "not json\n",
+			key:         key,
+			line:        2,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Verify(strings.NewReader(tc.content), tc.key)
+			require.Error(t, err)
+			vErr, ok := err.(*VerifyError)
+			require.True(t, ok, "expected a *VerifyError")
+			assert.Equal(t, tc.line, vErr.Line)
+		})
+	}
+
+	t.Run("the whole file with the right key", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := Verify(bytes.NewBufferString(strings.Join(lines, "")), key)
+		require.NoError(t, err)
+		assert.Equal(t, 3, res.Records)
+	})
+}
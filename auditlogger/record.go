@@ -0,0 +1,51 @@
+package auditlogger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// This file contains the format of the audit files.
+//
+// Each line of a file is a JSON object containing an entry, the SHA-256
+// of the entry, and optionally the HMAC-SHA256 of the hash:
+//
+//	{"entry":{"seq":1,...,"prev_hash":""},"hash":"...","hmac":"..."}
+//
+// The hash is computed on the exact bytes of the entry as they appear in
+// the file. Since every entry contains the hash of the previous one, any
+// deletion or modification breaks the chain.
+
+// record is a line of an audit file
+type record struct {
+	Entry json.RawMessage `json:"entry"`
+	Hash  string          `json:"hash"`
+	HMAC  string          `json:"hmac,omitempty"`
+}
+
+// auditEntry is the content of a record
+type auditEntry struct {
+	Seq      uint64                 `json:"seq"`
+	Time     string                 `json:"time"`
+	Level    string                 `json:"level"`
+	Tag      string                 `json:"tag,omitempty"`
+	Message  string                 `json:"message"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+	PrevHash string                 `json:"prev_hash"`
+}
+
+// hashEntry returns the hex-encoded SHA-256 of an encoded entry
+func hashEntry(entry []byte) string {
+	sum := sha256.Sum256(entry)
+	return hex.EncodeToString(sum[:])
+}
+
+// sign returns the hex-encoded HMAC-SHA256 of a hash
+func sign(key []byte, hash string) string {
+	mac := hmac.New(sha256.New, key)
+	// hash.Hash never returns an error
+	_, _ = mac.Write([]byte(hash)) //nolint:errcheck
+	return hex.EncodeToString(mac.Sum(nil))
+}
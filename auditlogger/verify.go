@@ -0,0 +1,86 @@
+package auditlogger
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// maxRecordSize is the maximum size of a line of an audit file
+const maxRecordSize = 10 * 1024 * 1024
+
+// Result contains the result of a successful verification
+type Result struct {
+	// Records is the number of records in the file
+	Records int
+
+	// LastHash is the hash of the last record. Deleting the last records
+	// of a file cannot be detected by Verify, so LastHash should be
+	// compared to a copy kept somewhere else
+	LastHash string
+}
+
+// VerifyError is returned by Verify when a file has been tampered with
+type VerifyError struct {
+	// Line is the line of the first invalid record, starting at 1
+	Line   int
+	Reason string
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// Verify reads an audit file, and checks that none of its records have
+// been modified, deleted, or inserted. If key is not nil, the HMAC of
+// every record is checked as well.
+// Returns a *VerifyError if the file has been tampered with
+func Verify(r io.Reader, key []byte) (*Result, error) {
+	res := &Result{}
+	var prevSeq uint64
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxRecordSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		fail := func(format string, args ...interface{}) error {
+			return &VerifyError{Line: line, Reason: fmt.Sprintf(format, args...)}
+		}
+
+		rec := &record{}
+		entry := &auditEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), rec); err != nil {
+			return nil, fail("invalid record: %s", err.Error())
+		}
+		if err := json.Unmarshal(rec.Entry, entry); err != nil {
+			return nil, fail("invalid entry: %s", err.Error())
+		}
+
+		if hash := hashEntry(rec.Entry); hash != rec.Hash {
+			return nil, fail("the entry has been modified")
+		}
+		if key != nil && !hmac.Equal([]byte(sign(key, rec.Hash)), []byte(rec.HMAC)) {
+			return nil, fail("invalid HMAC")
+		}
+		if entry.PrevHash != res.LastHash {
+			return nil, fail("the previous record is missing or has been modified")
+		}
+		if entry.Seq != prevSeq+1 {
+			return nil, fail("expected sequence %d, got %d", prevSeq+1, entry.Seq)
+		}
+
+		prevSeq = entry.Seq
+		res.LastHash = rec.Hash
+		res.Records++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read the audit file")
+	}
+	return res, nil
+}
@@ -0,0 +1,67 @@
+// Command auditverify checks the integrity of a file written by
+// auditlogger.AuditLogger.
+//
+// Usage:
+//
+//	auditverify [-key-file path] audit.log
+//
+// The exit code is 1 if the file has been tampered with, and 2 if it
+// could not be read
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/Nivl/go-logger/auditlogger"
+)
+
+func main() {
+	keyFile := flag.String("key-file", "", "file containing the HMAC key")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-key-file path] audit.log\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	os.Exit(run(flag.Arg(0), *keyFile))
+}
+
+func run(path, keyFile string) int {
+	var key []byte
+	if keyFile != "" {
+		data, err := ioutil.ReadFile(keyFile) //nolint:gosec // the path is provided by the user
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "could not read the key:", err)
+			return 2
+		}
+		key = bytes.TrimSpace(data)
+	}
+
+	f, err := os.Open(path) //nolint:gosec // the path is provided by the user
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer f.Close() //nolint:errcheck
+
+	res, err := auditlogger.Verify(f, key)
+	if err != nil {
+		if _, ok := err.(*auditlogger.VerifyError); ok {
+			fmt.Fprintln(os.Stderr, "tampered:", err)
+			return 1
+		}
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	fmt.Printf("OK: %d records, last hash %s\n", res.Records, res.LastHash)
+	return 0
+}
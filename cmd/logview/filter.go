@@ -0,0 +1,136 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/pkg/errors"
+)
+
+// operators contains the operators supported by the field expressions.
+// The operators starting with another operator must be listed first
+var operators = []string{"!=", ">=", "<=", "=", "~", ">", "<"}
+
+// expression is a condition on a field of an entry, like "status>=500"
+type expression struct {
+	key   string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+// parseExpression parses a field expression:
+//
+//	key        the field exists
+//	key=value  the field equals value
+//	key!=value the field doesn't equal value
+//	key~regexp the field matches regexp
+//	key>n      the field is a number greater than n (also >=, <, <=)
+func parseExpression(s string) (*expression, error) {
+	idx, op := -1, ""
+	for _, o := range operators {
+		if i := strings.Index(s, o); i >= 0 && (idx == -1 || i < idx || (i == idx && len(o) > len(op))) {
+			idx, op = i, o
+		}
+	}
+	if idx == -1 {
+		return &expression{key: s}, nil
+	}
+	if idx == 0 {
+		return nil, errors.Errorf("invalid expression %q: missing key", s)
+	}
+
+	expr := &expression{
+		key:   s[:idx],
+		op:    op,
+		value: s[idx+len(op):],
+	}
+	switch op {
+	case "~":
+		re, err := regexp.Compile(expr.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid expression %q", s)
+		}
+		expr.re = re
+	case ">", ">=", "<", "<=":
+		if _, err := strconv.ParseFloat(expr.value, 64); err != nil {
+			return nil, errors.Errorf("invalid expression %q: %s is not a number", s, expr.value)
+		}
+	}
+	return expr, nil
+}
+
+// match returns whether an entry matches the expression
+func (expr *expression) match(e *logger.Entry) bool {
+	v, ok := e.Fields[expr.key]
+	switch expr.key {
+	case "message", "msg":
+		v, ok = e.Message, true
+	case "tag":
+		v, ok = e.Tag, true
+	}
+	if expr.op == "" {
+		return ok
+	}
+	if !ok {
+		return expr.op == "!="
+	}
+
+	s := toString(v)
+	switch expr.op {
+	case "=":
+		return s == expr.value
+	case "!=":
+		return s != expr.value
+	case "~":
+		return expr.re.MatchString(s)
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return false
+	}
+	// the value has been validated by parseExpression
+	ref, _ := strconv.ParseFloat(expr.value, 64) //nolint:errcheck
+	switch expr.op {
+	case ">":
+		return n > ref
+	case ">=":
+		return n >= ref
+	case "<":
+		return n < ref
+	default:
+		return n <= ref
+	}
+}
+
+// filter contains all the conditions an entry must match to be printed
+type filter struct {
+	level       logger.Level
+	hasLevel    bool
+	tagPrefix   string
+	expressions []*expression
+}
+
+// active returns whether the filter has any condition
+func (f *filter) active() bool {
+	return f.hasLevel || f.tagPrefix != "" || len(f.expressions) > 0
+}
+
+// match returns whether an entry matches all the conditions
+func (f *filter) match(e *logger.Entry) bool {
+	if f.hasLevel && !e.Level.AtLeast(f.level) {
+		return false
+	}
+	if !strings.HasPrefix(e.Tag, f.tagPrefix) {
+		return false
+	}
+	for _, expr := range f.expressions {
+		if !expr.match(e) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression(t *testing.T) {
+	t.Parallel()
+
+	e := &logger.Entry{
+		Tag:     "[api]",
+		Message: "GET /api/users 503",
+		Fields: map[string]interface{}{
+			"status": json.Number("503"),
+			"path":   "/api/users",
+		},
+	}
+
+	testCases := []struct {
+		expression string
+		match      bool
+	}{
+		{expression: "status", match: true},
+		{expression: "user_id", match: false},
+		{expression: "status=503", match: true},
+		{expression: "status!=503", match: false},
+		{expression: "user_id!=42", match: true},
+		{expression: "status>=500", match: true},
+		{expression: "status<500", match: false},
+		{expression: "path~^/api/", match: true},
+		{expression: "path~a=b", match: false},
+		{expression: "msg~503$", match: true},
+		{expression: "tag=[api]", match: true},
+		{expression: "path>1", match: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.expression, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := parseExpression(tc.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tc.match, expr.match(e))
+		})
+	}
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		t.Parallel()
+
+		for _, s := range []string{"=value", "status>high", "path~("} {
+			_, err := parseExpression(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	status, err := parseExpression("status>=500")
+	require.NoError(t, err)
+	f := &filter{
+		level:       logger.LevelInfo,
+		hasLevel:    true,
+		tagPrefix:   "[api]",
+		expressions: []*expression{status},
+	}
+	assert.True(t, f.active())
+	assert.False(t, (&filter{}).active())
+
+	fields := map[string]interface{}{"status": json.Number("500")}
+	assert.True(t, f.match(&logger.Entry{Level: logger.LevelError, Tag: "[api][db]", Fields: fields}))
+	assert.False(t, f.match(&logger.Entry{Level: logger.LevelDebug, Tag: "[api]", Fields: fields}), "level")
+	assert.False(t, f.match(&logger.Entry{Level: logger.LevelError, Tag: "[auth]", Fields: fields}), "tag")
+	assert.False(t, f.match(&logger.Entry{Level: logger.LevelError, Tag: "[api]"}), "expression")
+}
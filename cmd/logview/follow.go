@@ -0,0 +1,120 @@
+package main
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// followInterval is the time waited before checking a followed file
+// for new data
+const followInterval = 250 * time.Millisecond
+
+// follower is an io.Reader that waits for new data at the end of a file,
+// in the manner of tail -F.
+// When the file is rotated (renamed or removed, then recreated) or
+// truncated, the new content is read from the beginning
+type follower struct {
+	path     string
+	f        *os.File
+	offset   int64
+	interval time.Duration
+	done     <-chan struct{}
+}
+
+// newFollower opens the file at path and returns a reader that follows
+// it until done is closed
+func newFollower(path string, done <-chan struct{}) (*follower, error) {
+	f, err := os.Open(path) //nolint:gosec // the path is provided by the user
+	if err != nil {
+		return nil, err
+	}
+	return &follower{
+		path:     path,
+		f:        f,
+		interval: followInterval,
+		done:     done,
+	}, nil
+}
+
+// Read implements io.Reader. It blocks until there is data to read, or
+// returns io.EOF once done is closed
+func (r *follower) Read(p []byte) (int, error) {
+	for {
+		n, err := r.f.Read(p)
+		r.offset += int64(n)
+		if n > 0 {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+
+		reopened, err := r.reopenIfRotated()
+		if err != nil {
+			return 0, err
+		}
+		if reopened {
+			continue
+		}
+
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// reopenIfRotated reopens the file if it has been replaced or truncated
+// since it was opened.
+// Returns whether the file has been reopened
+func (r *follower) reopenIfRotated() (bool, error) {
+	current, err := r.f.Stat()
+	if err != nil {
+		return false, errors.Wrap(err, "could not stat the file")
+	}
+	info, err := os.Stat(r.path)
+	if err != nil {
+		// the file is being rotated, and will be back soon
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "could not stat the file")
+	}
+
+	if os.SameFile(current, info) {
+		if info.Size() >= r.offset {
+			return false, nil
+		}
+		if _, err := r.f.Seek(0, io.SeekStart); err != nil {
+			return false, errors.Wrap(err, "could not rewind the file")
+		}
+		r.offset = 0
+		return true, nil
+	}
+
+	// the previous file may have been written to before being rotated
+	if current.Size() > r.offset {
+		return true, nil
+	}
+	f, err := os.Open(r.path) //nolint:gosec // the path is provided by the user
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "could not open the file")
+	}
+	// Nothing useful can be done if the old file cannot be closed
+	_ = r.f.Close() //nolint:errcheck
+	r.f = f
+	r.offset = 0
+	return true, nil
+}
+
+// Close closes the followed file
+func (r *follower) Close() error {
+	return r.f.Close()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollower(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "logview")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) //nolint:errcheck
+	path := filepath.Join(dir, "app.log")
+	require.NoError(t, ioutil.WriteFile(path, []byte("first\n"), 0600))
+
+	done := make(chan struct{})
+	r, err := newFollower(path, done)
+	require.NoError(t, err)
+	defer r.Close() //nolint:errcheck
+	r.interval = time.Millisecond
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	next := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a line")
+		}
+		return ""
+	}
+
+	assert.Equal(t, "first", next())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString("appended\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, "appended", next())
+
+	// rotation
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, ioutil.WriteFile(path, []byte("rotated\n"), 0600))
+	assert.Equal(t, "rotated", next())
+
+	// truncation
+	require.NoError(t, ioutil.WriteFile(path, []byte("new\n"), 0600))
+	assert.Equal(t, "new", next())
+
+	close(done)
+	_, ok := <-lines
+	assert.False(t, ok, "the reader should stop once done is closed")
+}
@@ -0,0 +1,213 @@
+// Command logview prints JSON logs in a human-friendly way.
+//
+// It reads JSON lines from files, or from stdin, and understands the
+// output of the formatters of github.com/Nivl/go-logger, the audit files
+// of auditlogger, and the common keys used by other libraries (time, ts,
+// level, msg, etc.). Lines that are not JSON are printed as they are,
+// unless a filter is used.
+//
+// Usage:
+//
+//	logview [flags] [file...]
+//
+// Examples:
+//
+//	logview -level info -tag '[auth]' app.log
+//	logview -where 'status>=500' -where 'path~^/api' app.log
+//	logview --follow -output logfmt /var/log/app.log
+//	kubectl logs my-pod | logview -where user_id=42
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/pkg/errors"
+)
+
+// maxLineSize is the maximum size of a line
+const maxLineSize = 10 * 1024 * 1024
+
+// defaultTimeFormat is the layout used to print the time of the entries
+// in the console output
+const defaultTimeFormat = "2006-01-02 15:04:05.000"
+
+// expressionsFlag is a flag that can be set multiple times
+type expressionsFlag []*expression
+
+func (f *expressionsFlag) String() string {
+	return ""
+}
+
+func (f *expressionsFlag) Set(s string) error {
+	expr, err := parseExpression(s)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, expr)
+	return nil
+}
+
+func main() {
+	var (
+		level       = flag.String("level", "", "only print the entries of this level or above (debug, info, log, error)")
+		tag         = flag.String("tag", "", "only print the entries whose tag starts with this prefix")
+		follow      = flag.Bool("follow", false, "wait for new entries, following the file when it's rotated")
+		output      = flag.String("output", "console", "output format: console, logfmt or json")
+		noColor     = flag.Bool("no-color", false, "disable the colors of the console output")
+		timeFormat  = flag.String("time-format", defaultTimeFormat, "layout used to print the time in the console output")
+		expressions expressionsFlag
+	)
+	flag.BoolVar(follow, "f", false, "shorthand for -follow")
+	flag.Var(&expressions, "where", "only print the entries matching a field expression: key, key=value, key!=value, key~regexp, key>n, key>=n, key<n, key<=n. Can be repeated")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [file...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	f := &filter{
+		tagPrefix:   *tag,
+		expressions: expressions,
+	}
+	if *level != "" {
+		lvl, err := logger.ParseLevel(*level)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		f.level = lvl
+		f.hasLevel = true
+	}
+
+	formatter, err := newFormatter(*output, *timeFormat, !*noColor)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if err := run(flag.Args(), *follow, f, formatter); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// newFormatter returns the formatter matching the output flag
+func newFormatter(output, timeFormat string, color bool) (logger.Formatter, error) {
+	switch output {
+	case "console":
+		f := logger.NewConsoleFormatter(os.Stdout).(*logger.ConsoleFormatter)
+		f.Color = f.Color && color
+		f.TimeFormat = timeFormat
+		return f, nil
+	case "logfmt":
+		return logger.NewLogfmtFormatter(), nil
+	case "json":
+		return logger.NewECSFormatter(), nil
+	}
+	return nil, errors.Errorf("unknown output %q", output)
+}
+
+// run prints the content of the given files, or of stdin if there are
+// none
+func run(paths []string, follow bool, f *filter, formatter logger.Formatter) error {
+	out := bufio.NewWriter(os.Stdout)
+	// Nothing useful can be done if stdout is gone
+	defer out.Flush() //nolint:errcheck
+
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	if follow && (len(paths) != 1 || paths[0] == "-") {
+		return errors.New("-f requires exactly one file")
+	}
+
+	if follow {
+		done := make(chan struct{})
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		go func() {
+			<-sig
+			close(done)
+		}()
+
+		r, err := newFollower(paths[0], done)
+		if err != nil {
+			return err
+		}
+		defer r.Close() //nolint:errcheck
+		// the output is flushed after every line
+		return view(r, &lineFlusher{out}, f, formatter)
+	}
+
+	for _, path := range paths {
+		if err := viewFile(path, out, f, formatter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// viewFile prints the content of a file, or of stdin if path is "-"
+func viewFile(path string, w io.Writer, f *filter, formatter logger.Formatter) error {
+	if path == "-" {
+		return view(os.Stdin, w, f, formatter)
+	}
+
+	r, err := os.Open(path) //nolint:gosec // the path is provided by the user
+	if err != nil {
+		return err
+	}
+	defer r.Close() //nolint:errcheck
+	return errors.Wrap(view(r, w, f, formatter), path)
+}
+
+// view reads JSON lines from r, and prints the entries matching f on w
+func view(r io.Reader, w io.Writer, f *filter, formatter logger.Formatter) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		e, ok := parseLine(line)
+		if !ok {
+			if f.active() || strings.TrimSpace(string(line)) == "" {
+				continue
+			}
+			if _, err := w.Write(append(line, '\n')); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.match(e) {
+			continue
+		}
+
+		data, err := formatter.Format(e)
+		if err != nil {
+			return errors.Wrap(err, "could not format the entry")
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// lineFlusher is a writer that flushes a bufio.Writer after every write
+type lineFlusher struct {
+	w *bufio.Writer
+}
+
+func (l *lineFlusher) Write(p []byte) (int, error) {
+	n, err := l.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, l.w.Flush()
+}
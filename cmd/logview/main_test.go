@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestView(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Join([]string{
+		`{"@timestamp":"2019-05-04T10:30:00Z","log.level":"info","log.logger":"[api]","message":"started"}`,
+		`panic: not a JSON line`,
+		`{"time":"2019-05-04T10:30:01Z","level":"error","tag":"[db]","msg":"connection lost","host":"db-1"}`,
+	}, "\n")
+
+	t.Run("everything is printed without filters", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		require.NoError(t, view(strings.NewReader(input), &out, &filter{}, logger.NewLogfmtFormatter()))
+		expected := "time=2019-05-04T10:30:00Z level=info tag=[api] msg=started\n" +
+			"panic: not a JSON line\n" +
+			"time=2019-05-04T10:30:01Z level=error tag=[db] msg=\"connection lost\" host=db-1\n"
+		assert.Equal(t, expected, out.String())
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		f := &filter{level: logger.LevelError, hasLevel: true}
+		require.NoError(t, view(strings.NewReader(input), &out, f, logger.NewLogfmtFormatter()))
+		assert.Equal(t, "time=2019-05-04T10:30:01Z level=error tag=[db] msg=\"connection lost\" host=db-1\n", out.String())
+	})
+
+	t.Run("console output", func(t *testing.T) {
+		t.Parallel()
+
+		formatter, err := newFormatter("console", defaultTimeFormat, false)
+		require.NoError(t, err)
+		var out bytes.Buffer
+		require.NoError(t, view(strings.NewReader(input), &out, &filter{tagPrefix: "[db]"}, formatter))
+		assert.Contains(t, out.String(), "ERROR [db] connection lost\n    host: db-1\n")
+	})
+
+	t.Run("unknown output", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newFormatter("xml", defaultTimeFormat, false)
+		assert.Error(t, err)
+	})
+}
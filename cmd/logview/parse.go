@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	logger "github.com/Nivl/go-logger"
+)
+
+// Keys used to find the standard attributes of an entry. The first one
+// found is used, and the others are kept as fields
+var (
+	timeKeys    = []string{"@timestamp", "time", "timestamp", "ts"}
+	levelKeys   = []string{"log.level", "level", "lvl", "severity"}
+	messageKeys = []string{"message", "msg"}
+	tagKeys     = []string{"log.logger", "tag", "logger"}
+)
+
+// nestedKeys contains the objects whose content is added to the fields,
+// like the labels of ECSFormatter or the data of the audit logs
+var nestedKeys = []string{"labels", "data"}
+
+// ignoredKeys contains the keys that are not useful to a reader
+var ignoredKeys = []string{"ecs.version"}
+
+// parseLine returns the entry contained in a JSON line.
+// Returns false if the line doesn't contain a JSON object
+func parseLine(line []byte) (*logger.Entry, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+
+	doc := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false
+	}
+
+	// records of auditlogger contain the entry next to its hash
+	if entry, ok := doc["entry"].(map[string]interface{}); ok {
+		if _, ok := doc["hash"]; ok {
+			doc = entry
+		}
+	}
+
+	e := &logger.Entry{
+		Fields: map[string]interface{}{},
+	}
+	if v, ok := take(doc, timeKeys); ok {
+		e.Time = parseTime(v)
+	}
+	if v, ok := take(doc, levelKeys); ok {
+		e.Level = parseLevel(v)
+	}
+	if v, ok := take(doc, messageKeys); ok {
+		e.Message = toString(v)
+	}
+	if v, ok := take(doc, tagKeys); ok {
+		e.Tag = toString(v)
+	}
+	for _, k := range ignoredKeys {
+		delete(doc, k)
+	}
+	for _, k := range nestedKeys {
+		if nested, ok := doc[k].(map[string]interface{}); ok {
+			delete(doc, k)
+			for nk, nv := range nested {
+				e.Fields[nk] = nv
+			}
+		}
+	}
+	// ECSFormatter stores errors in an object
+	if ecsErr, ok := doc["error"].(map[string]interface{}); ok {
+		if msg, ok := ecsErr["message"].(string); ok {
+			doc["error"] = msg
+		}
+	}
+	for k, v := range doc {
+		e.Fields[k] = v
+	}
+	return e, true
+}
+
+// take removes and returns the value of the first key of keys found in
+// doc
+func take(doc map[string]interface{}, keys []string) (interface{}, bool) {
+	for _, k := range keys {
+		if v, ok := doc[k]; ok {
+			delete(doc, k)
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// parseTime returns the time contained in v, which can be a RFC 3339
+// date, or a number of seconds since the epoch.
+// Returns the zero time if v cannot be parsed
+func parseTime(v interface{}) time.Time {
+	switch v := v.(type) {
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err == nil {
+			return t
+		}
+	case json.Number:
+		secs, err := v.Float64()
+		if err == nil {
+			return time.Unix(0, int64(secs*float64(time.Second)))
+		}
+	}
+	return time.Time{}
+}
+
+// parseLevel returns the level matching v, including the names used
+// by other libraries
+func parseLevel(v interface{}) logger.Level {
+	name := strings.ToLower(toString(v))
+	switch name {
+	case "trace":
+		return logger.LevelDebug
+	case "warn", "warning", "notice":
+		return logger.LevelDefault
+	case "fatal", "panic", "critical", "crit", "err":
+		return logger.LevelError
+	}
+	lvl, err := logger.ParseLevel(name)
+	if err != nil {
+		return logger.LevelDefault
+	}
+	return lvl
+}
+
+// toString returns the string representation of a JSON value
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	case json.Number:
+		return v.String()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
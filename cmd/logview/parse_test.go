@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		description string
+		line        string
+		expected    *logger.Entry
+	}{
+		{
+			description: "ECS document",
+			line:        `{"@timestamp":"2019-05-04T10:30:00Z","log.level":"error","log.logger":"[app]","message":"not found","ecs.version":"1.6.0","labels":{"user_id":42},"error":{"message":"no rows","type":"*errors.fundamental"}}`,
+			expected: &logger.Entry{
+				Time:    time.Date(2019, 5, 4, 10, 30, 0, 0, time.UTC),
+				Level:   logger.LevelError,
+				Tag:     "[app]",
+				Message: "not found",
+				Fields:  map[string]interface{}{"user_id": json.Number("42"), "error": "no rows"},
+			},
+		},
+		{
+			description: "audit record",
+			line:        `{"entry":{"seq":1,"time":"2019-05-04T10:30:00Z","level":"INFO","tag":"[auth]","message":"logged in","prev_hash":""},"hash":"abc"}`,
+			expected: &logger.Entry{
+				Time:    time.Date(2019, 5, 4, 10, 30, 0, 0, time.UTC),
+				Level:   logger.LevelInfo,
+				Tag:     "[auth]",
+				Message: "logged in",
+				Fields:  map[string]interface{}{"seq": json.Number("1"), "prev_hash": ""},
+			},
+		},
+		{
+			description: "other libraries",
+			line:        `{"ts":1556965800.5,"lvl":"warn","msg":"slow query"}`,
+			expected: &logger.Entry{
+				Time:    time.Unix(1556965800, 500000000),
+				Level:   logger.LevelDefault,
+				Message: "slow query",
+				Fields:  map[string]interface{}{},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			e, ok := parseLine([]byte(tc.line))
+			require.True(t, ok)
+			assert.True(t, tc.expected.Time.Equal(e.Time), "wrong time")
+			e.Time = tc.expected.Time
+			assert.Equal(t, tc.expected, e)
+		})
+	}
+
+	t.Run("not JSON", func(t *testing.T) {
+		t.Parallel()
+
+		for _, line := range []string{"", "panic: oops", `{"invalid`} {
+			_, ok := parseLine([]byte(line))
+			assert.False(t, ok, line)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// we make sure combinedLogger implements EntryLogger and Flusher
+var (
+	_ EntryLogger = (*combinedLogger)(nil)
+	_ Flusher     = (*combinedLogger)(nil)
+)
+
+// Tee returns a logger that sends the entries to all the given loggers.
+// Its ID is made of the IDs of the loggers
+func Tee(loggers ...Logger) Logger {
+	ids := make([]string, len(loggers))
+	for i, l := range loggers {
+		ids[i] = l.ID()
+	}
+
+	return newCombinedLogger("tee("+strings.Join(ids, ",")+")", loggers, func(e *Entry, next []Logger) error {
+		msg := textFunc(e)
+		var errs []string
+		for _, l := range next {
+			if err := writeEntry(l, e, msg); err != nil {
+				errs = append(errs, l.ID()+": "+err.Error())
+			}
+		}
+		return combineErrors("some loggers failed", errs)
+	})
+}
+
+// Filter returns a logger that only sends the entries matching predicate
+// to l.
+// Its ID is unique, so several filters can wrap the same logger
+func Filter(predicate func(e *Entry) bool, l Logger) Logger {
+	return newCombinedLogger("filter("+l.ID()+"):"+uuid.New().String(), []Logger{l}, func(e *Entry, next []Logger) error {
+		if !predicate(e) {
+			return nil
+		}
+		return writeEntry(next[0], e, textFunc(e))
+	})
+}
+
+// LevelFilter returns a logger that only sends the entries of the given
+// level, or above, to l
+func LevelFilter(min Level, l Logger) Logger {
+	return newCombinedLogger("level-filter("+min.String()+","+l.ID()+")", []Logger{l}, func(e *Entry, next []Logger) error {
+		if !e.Level.AtLeast(min) {
+			return nil
+		}
+		return writeEntry(next[0], e, textFunc(e))
+	})
+}
+
+// MapMessage returns a logger that replaces the message of the entries
+// by the value returned by fn, before sending them to l.
+// Its ID is unique, so several mappers can wrap the same logger
+func MapMessage(fn func(msg string) string, l Logger) Logger {
+	return newCombinedLogger("map-message("+l.ID()+"):"+uuid.New().String(), []Logger{l}, func(e *Entry, next []Logger) error {
+		// The entry is shared with the other loggers, so we work on a copy
+		mapped := *e
+		mapped.Message = fn(e.Message)
+		return writeEntry(next[0], &mapped, textFunc(&mapped))
+	})
+}
+
+// Once returns a logger that only sends the first occurrence of each
+// entry to l. Two entries are the same if they have the same level, tag
+// and message.
+// All the entries seen are kept in memory
+func Once(l Logger) Logger {
+	var mu sync.Mutex
+	seen := map[onceKey]struct{}{}
+
+	return newCombinedLogger("once("+l.ID()+")", []Logger{l}, func(e *Entry, next []Logger) error {
+		key := onceKey{level: e.Level, tag: e.Tag, msg: e.Message}
+		mu.Lock()
+		_, ok := seen[key]
+		seen[key] = struct{}{}
+		mu.Unlock()
+
+		if ok {
+			return nil
+		}
+		return writeEntry(next[0], e, textFunc(e))
+	})
+}
+
+// onceKey is the key used by Once to identify an entry
+type onceKey struct {
+	level Level
+	tag   string
+	msg   string
+}
+
+// combinedLogger is a go-routine safe logger that wraps other loggers.
+// Closing a combinedLogger closes the loggers it wraps
+type combinedLogger struct {
+	id     string
+	next   []Logger
+	handle func(e *Entry, next []Logger) error
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// newCombinedLogger returns a logger that calls handle with the open
+// loggers of next for every entry
+func newCombinedLogger(id string, next []Logger, handle func(e *Entry, next []Logger) error) *combinedLogger {
+	return &combinedLogger{
+		id:     id,
+		next:   next,
+		handle: handle,
+	}
+}
+
+// ID returns the logger's unique ID
+func (l *combinedLogger) ID() string {
+	return l.id
+}
+
+// Close closes the loggers wrapped by the logger
+// the logger may not be reusable after being closed
+func (l *combinedLogger) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	l.mu.Unlock()
+
+	var errs []string
+	for _, next := range l.next {
+		if err := next.Close(); err != nil {
+			errs = append(errs, next.ID()+": "+err.Error())
+		}
+	}
+	return combineErrors("could not close the loggers", errs)
+}
+
+// IsClosed returns wether the logger is closed or not. The logger is
+// also closed once all the loggers it wraps are closed
+func (l *combinedLogger) IsClosed() bool {
+	l.mu.Lock()
+	closed := l.closed
+	l.mu.Unlock()
+
+	if closed {
+		return true
+	}
+	if len(l.next) == 0 {
+		return false
+	}
+	for _, next := range l.next {
+		if !next.IsClosed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Flush flushes the wrapped loggers implementing Flusher
+func (l *combinedLogger) Flush() error {
+	var errs []string
+	for _, next := range l.next {
+		if f, ok := next.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, next.ID()+": "+err.Error())
+			}
+		}
+	}
+	return combineErrors("could not flush the loggers", errs)
+}
+
+// LogEntry sends the entry to the wrapped loggers that are still open
+func (l *combinedLogger) LogEntry(e *Entry) error {
+	if l.IsClosed() {
+		return errors.New("logger is closed")
+	}
+
+	next := make([]Logger, 0, len(l.next))
+	for _, n := range l.next {
+		if !n.IsClosed() {
+			next = append(next, n)
+		}
+	}
+	return l.handle(e, next)
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (l *combinedLogger) Error(msg string) {
+	WriteLevel(l, msg, LevelError)
+}
+
+// Info logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Println.
+func (l *combinedLogger) Info(msg string) {
+	WriteLevel(l, msg, LevelInfo)
+}
+
+// Debug logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Println.
+func (l *combinedLogger) Debug(msg string) {
+	WriteLevel(l, msg, LevelDebug)
+}
+
+// Log logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Println.
+func (l *combinedLogger) Log(msg string) {
+	WriteLevel(l, msg, LevelDefault)
+}
+
+// textFunc returns a function that returns the text representation of
+// an entry, computing it only once
+func textFunc(e *Entry) func() string {
+	var text *string
+	return func() string {
+		if text == nil {
+			s := entryText(e)
+			text = &s
+		}
+		return *text
+	}
+}
+
+// combineErrors returns an error containing all the given messages, or
+// nil if there are none
+func combineErrors(msg string, errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New(msg + ": " + strings.Join(errs, ", "))
+}
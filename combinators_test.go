@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	a := &entrySliceLogger{SliceLogger: SliceLogger{id: "a"}}
+	b := &SliceLogger{id: "b"}
+	failing := &failingLogger{SliceLogger: SliceLogger{id: "failing"}, err: errors.New("disk full")}
+	l := Tee(a, b, failing)
+	assert.Equal(t, "tee(a,b,failing)", l.ID())
+
+	m := NewManagerWithTag("[app]")
+	require.NoError(t, m.Add(l))
+	var sinkErr error
+	m.OnSinkError(func(_ Logger, _ *Entry, err error) {
+		sinkErr = err
+	})
+
+	m.Info("message")
+	assert.Len(t, a.entries, 1)
+	assert.Equal(t, []string{"[INFO][app] message\n"}, b.data)
+	require.Error(t, sinkErr, "the error of failing should have been reported")
+	assert.Contains(t, sinkErr.Error(), "disk full")
+
+	// closed loggers are skipped
+	require.NoError(t, b.Close())
+	m.Info("message")
+	assert.Len(t, a.entries, 2)
+	assert.Empty(t, b.data)
+	assert.False(t, l.IsClosed(), "some loggers are still open")
+
+	require.NoError(t, l.Close())
+	assert.True(t, l.IsClosed())
+	assert.True(t, a.IsClosed())
+	assert.True(t, failing.IsClosed())
+
+	assert.False(t, Tee().IsClosed(), "an empty tee is not closed")
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	sl := &entrySliceLogger{SliceLogger: SliceLogger{id: "sink"}}
+	l := Filter(func(e *Entry) bool {
+		return e.Tag == "[db]"
+	}, sl)
+	assert.True(t, strings.HasPrefix(l.ID(), "filter(sink):"), "unexpected ID "+l.ID())
+
+	m := NewManager()
+	require.NoError(t, m.Add(l))
+	m.Info("dropped")
+	m.NewSubManager("[db]").Info("kept")
+
+	require.Len(t, sl.entries, 1)
+	assert.Equal(t, "kept", sl.entries[0].Message)
+}
+
+func TestFilterSameLogger(t *testing.T) {
+	t.Parallel()
+
+	sl := &entrySliceLogger{SliceLogger: SliceLogger{id: "sink"}}
+	db := Filter(func(e *Entry) bool {
+		return e.Tag == "[db]"
+	}, sl)
+	http := Filter(func(e *Entry) bool {
+		return e.Tag == "[http]"
+	}, sl)
+	assert.NotEqual(t, db.ID(), http.ID())
+
+	m := NewManager()
+	require.NoError(t, m.AddShared(db))
+	require.NoError(t, m.AddShared(http), "filters wrapping the same logger should have different IDs")
+	m.Info("dropped")
+	m.NewSubManager("[db]").Info("db")
+	m.NewSubManager("[http]").Info("http")
+
+	require.Len(t, sl.entries, 2)
+	assert.Equal(t, "db", sl.entries[0].Message)
+	assert.Equal(t, "http", sl.entries[1].Message)
+}
+
+func TestLevelFilter(t *testing.T) {
+	t.Parallel()
+
+	sl := &SliceLogger{id: "sink"}
+	l := LevelFilter(LevelInfo, sl)
+	assert.Equal(t, "level-filter(INFO,sink)", l.ID())
+
+	l.Debug("dropped")
+	l.Info("info")
+	l.Log("log")
+	l.Error("error")
+	assert.Equal(t, []string{"[INFO]info\n", "log\n", "[ERROR]error\n"}, sl.data)
+
+	// closing the wrapped logger closes the filter
+	require.NoError(t, sl.Close())
+	assert.True(t, l.IsClosed())
+}
+
+func TestMapMessage(t *testing.T) {
+	t.Parallel()
+
+	mapped := &entrySliceLogger{SliceLogger: SliceLogger{id: "mapped"}}
+	raw := &entrySliceLogger{SliceLogger: SliceLogger{id: "raw"}}
+	l := MapMessage(strings.ToUpper, mapped)
+	assert.True(t, strings.HasPrefix(l.ID(), "map-message(mapped):"), "unexpected ID "+l.ID())
+	assert.NotEqual(t, l.ID(), MapMessage(strings.ToLower, mapped).ID(), "mappers wrapping the same logger should have different IDs")
+
+	m := NewManager()
+	require.NoError(t, m.Add(l))
+	require.NoError(t, m.Add(raw))
+	m.Info("message")
+
+	require.Len(t, mapped.entries, 1)
+	require.Len(t, raw.entries, 1)
+	assert.Equal(t, "MESSAGE", mapped.entries[0].Message)
+	assert.Equal(t, "message", raw.entries[0].Message, "the original entry should not change")
+}
+
+func TestOnce(t *testing.T) {
+	t.Parallel()
+
+	sl := &SliceLogger{id: "sink"}
+	l := Once(sl)
+	assert.Equal(t, "once(sink)", l.ID())
+
+	l.Info("message")
+	l.Info("message")
+	l.Error("message")
+	l.Info("other")
+	assert.Equal(t, []string{"[INFO]message\n", "[ERROR]message\n", "[INFO]other\n"}, sl.data)
+}
+
+func TestCombinatorsPipeline(t *testing.T) {
+	t.Parallel()
+
+	errs := &SliceLogger{id: "errors"}
+	all := &SliceLogger{id: "all"}
+	l := Tee(
+		LevelFilter(LevelError, Once(errs)),
+		MapMessage(func(msg string) string { return "> " + msg }, all),
+	)
+
+	m := NewManager()
+	require.NoError(t, m.Add(l))
+	m.Error("failed")
+	m.Error("failed")
+	m.Info("done")
+
+	assert.Equal(t, []string{"[ERROR]failed\n"}, errs.data)
+	assert.Equal(t, []string{"[ERROR]> failed\n", "[ERROR]> failed\n", "[INFO]> done\n"}, all.data)
+
+	require.Empty(t, m.Close())
+	assert.True(t, errs.IsClosed())
+	assert.True(t, all.IsClosed())
+}
@@ -0,0 +1,203 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultConsoleTimeFormat is the layout used by ConsoleFormatter to
+// print the time of the entries
+const DefaultConsoleTimeFormat = "15:04:05.000"
+
+// ANSI escape codes used to color the output
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorCyan   = "\x1b[36m"
+	colorGray   = "\x1b[90m"
+)
+
+// we make sure ConsoleFormatter implements Formatter
+var _ Formatter = (*ConsoleFormatter)(nil)
+
+// NewConsoleLogger creates and returns a logger that writes human-friendly
+// entries on stderr
+func NewConsoleLogger() Logger {
+	return NewWriterLogger(os.Stderr, NewConsoleFormatter(os.Stderr))
+}
+
+// NewConsoleFormatter creates and returns a human-friendly formatter meant
+// to be used in a terminal.
+// Colors are enabled if w is a terminal and if the NO_COLOR environment
+// variable is not set
+func NewConsoleFormatter(w io.Writer) Formatter {
+	return &ConsoleFormatter{
+		Color:      supportsColor(w),
+		TimeFormat: DefaultConsoleTimeFormat,
+		MessagePolicy: MessagePolicy{
+			Multiline: MultilineIndent,
+		},
+		start: time.Now(),
+		now:   time.Now,
+	}
+}
+
+// ConsoleFormatter is a formatter that prints human-friendly entries,
+// meant to be used during development:
+//
+//	15:04:05.000 ERROR [app][parser] message
+//	    key: value
+//
+// Tags are aligned on the longest one seen so far, and globals are
+// printed on their own line, below the message. The continuation lines
+// of the messages are indented by default.
+// The exported fields must not be changed once the formatter is in use
+type ConsoleFormatter struct {
+	MessagePolicy
+
+	// Color sets whether the levels and keys should be colored
+	Color bool
+
+	// TimeFormat is the layout used to print the time of the entries.
+	// No time is printed if empty
+	TimeFormat string
+
+	// RelativeTime prints the time elapsed since the creation of the
+	// formatter instead of the time of the entries
+	RelativeTime bool
+
+	// TagWidth is the minimum width of the tags
+	TagWidth int
+
+	mu       sync.Mutex
+	maxWidth int
+	start    time.Time
+	now      Clock // used for the entries that don't have a time
+}
+
+// Format returns the human-friendly representation of an entry
+func (f *ConsoleFormatter) Format(e *Entry) ([]byte, error) {
+	return f.formatMessage(e, f.format)
+}
+
+// format returns the human-friendly representation of an entry, once
+// the message policy has been applied
+func (f *ConsoleFormatter) format(e *Entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if f.RelativeTime {
+		elapsed := entryTime(e, f.now).Sub(f.start)
+		fmt.Fprintf(&buf, "%10s ", fmt.Sprintf("+%.3fs", elapsed.Seconds()))
+	} else if f.TimeFormat != "" {
+		buf.WriteString(entryTime(e, f.now).Format(f.TimeFormat))
+		buf.WriteByte(' ')
+	}
+
+	f.writeColored(&buf, levelColor(e.Level), fmt.Sprintf("%-5s", e.Level.String()))
+	buf.WriteByte(' ')
+
+	if width := f.tagWidth(e.Tag); width > 0 {
+		fmt.Fprintf(&buf, "%-*s ", width, e.Tag)
+	}
+
+	buf.WriteString(e.Message)
+	buf.WriteByte('\n')
+
+	data := e.Data()
+	for _, k := range sortedKeys(data) {
+		value := consoleValue(k, data[k])
+		buf.WriteString("    ")
+		f.writeColored(&buf, colorCyan, k)
+		buf.WriteString(": ")
+		// multi-line values are indented under their key
+		buf.WriteString(strings.Replace(value, "\n", "\n      ", -1))
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// tagWidth returns the width the given tag should be padded to
+func (f *ConsoleFormatter) tagWidth(tag string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(tag) > f.maxWidth {
+		f.maxWidth = len(tag)
+	}
+	if f.TagWidth > f.maxWidth {
+		return f.TagWidth
+	}
+	return f.maxWidth
+}
+
+func (f *ConsoleFormatter) writeColored(buf *bytes.Buffer, color, s string) {
+	if !f.Color {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteString(color)
+	buf.WriteString(s)
+	buf.WriteString(colorReset)
+}
+
+// consoleValue returns the human-friendly representation of a global.
+// Strings, numbers and errors are printed as they are, and everything
+// else is printed as indented JSON
+func consoleValue(k string, v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	case nil, bool,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return fmt.Sprint(v)
+	}
+
+	data, err := json.MarshalIndent(encodableValue(k, v), "", "  ")
+	if err != nil {
+		// Can't happen since the value has been checked
+		return unencodablePlaceholder(v)
+	}
+	return string(data)
+}
+
+func levelColor(lvl Level) string {
+	switch lvl {
+	case LevelDebug:
+		return colorGray
+	case LevelInfo:
+		return colorBlue
+	case LevelError:
+		return colorRed
+	default:
+		return colorYellow
+	}
+}
+
+// supportsColor returns whether colors should be used when writing on w
+// See https://no-color.org
+func supportsColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isTerminal(f.Fd())
+}
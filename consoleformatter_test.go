@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleFormatter(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2019, 5, 4, 10, 30, 0, 0, time.UTC)
+	newFormatter := func() *ConsoleFormatter {
+		f := NewConsoleFormatter(&bytes.Buffer{}).(*ConsoleFormatter)
+		f.start = start
+		f.now = func() time.Time { return start.Add(1500 * time.Millisecond) }
+		return f
+	}
+
+	t.Run("simple entry", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := newFormatter().Format(&Entry{
+			Level:   LevelInfo,
+			Message: "message",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "10:30:01.500 INFO  message\n", string(data))
+	})
+
+	t.Run("tags are aligned", func(t *testing.T) {
+		t.Parallel()
+
+		f := newFormatter()
+		f.TimeFormat = ""
+
+		data, err := f.Format(&Entry{Level: LevelError, Tag: "[app][parser]", Message: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, "ERROR [app][parser] a\n", string(data))
+
+		data, err = f.Format(&Entry{Level: LevelDebug, Tag: "[app]", Message: "b"})
+		require.NoError(t, err)
+		assert.Equal(t, "DEBUG [app]         b\n", string(data))
+	})
+
+	t.Run("relative time", func(t *testing.T) {
+		t.Parallel()
+
+		f := newFormatter()
+		f.RelativeTime = true
+
+		data, err := f.Format(&Entry{Message: "message"})
+		require.NoError(t, err)
+		assert.Equal(t, "   +1.500s LOG   message\n", string(data))
+	})
+
+	t.Run("globals are printed on their own line", func(t *testing.T) {
+		t.Parallel()
+
+		f := newFormatter()
+		f.TimeFormat = ""
+
+		data, err := f.Format(&Entry{
+			Level:   LevelError,
+			Message: "message",
+			Globals: map[string]interface{}{
+				"user":  "john",
+				"err":   errors.New("not found"),
+				"count": 2,
+				"req":   map[string]string{"method": "GET"},
+			},
+		})
+		require.NoError(t, err)
+		expected := "ERROR message\n" +
+			"    count: 2\n" +
+			"    err: not found\n" +
+			"    req: {\n" +
+			"        \"method\": \"GET\"\n" +
+			"      }\n" +
+			"    user: john\n"
+		assert.Equal(t, expected, string(data))
+	})
+
+	t.Run("colors", func(t *testing.T) {
+		t.Parallel()
+
+		f := newFormatter()
+		f.TimeFormat = ""
+		f.Color = true
+
+		data, err := f.Format(&Entry{
+			Level:   LevelError,
+			Message: "message",
+			Globals: map[string]interface{}{"key": "value"},
+		})
+		require.NoError(t, err)
+		expected := colorRed + "ERROR" + colorReset + " message\n" +
+			"    " + colorCyan + "key" + colorReset + ": value\n"
+		assert.Equal(t, expected, string(data))
+	})
+}
+
+func TestSupportsColor(t *testing.T) {
+	term := os.Getenv("TERM")
+	os.Setenv("TERM", "xterm")    //nolint:errcheck
+	defer os.Setenv("TERM", term) //nolint:errcheck
+
+	assert.False(t, supportsColor(&bytes.Buffer{}), "buffers are not terminals")
+
+	f, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer f.Close() //nolint:errcheck
+	assert.False(t, supportsColor(f), os.DevNull+" is a character device, but not a terminal")
+
+	// the master side of a pseudo terminal is a terminal
+	tty, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skip("pseudo terminals are not available: ", err)
+	}
+	defer tty.Close() //nolint:errcheck
+	assert.True(t, supportsColor(tty), "colors should be enabled on a terminal")
+
+	os.Setenv("NO_COLOR", "1")    //nolint:errcheck
+	defer os.Unsetenv("NO_COLOR") //nolint:errcheck
+	assert.False(t, supportsColor(tty), "NO_COLOR should disable the colors")
+}
@@ -0,0 +1,22 @@
+package logger
+
+import (
+	"context"
+)
+
+// managerContextKey is the key used to store a Manager in a context
+type managerContextKey struct{}
+
+// NewContext returns a copy of ctx that contains m
+func NewContext(ctx context.Context, m Manager) context.Context {
+	return context.WithValue(ctx, managerContextKey{}, m)
+}
+
+// FromContext returns the manager stored in ctx by NewContext, or the
+// default manager if ctx doesn't contain any
+func FromContext(ctx context.Context) Manager {
+	if m, ok := ctx.Value(managerContextKey{}).(Manager); ok {
+		return m
+	}
+	return Default()
+}
@@ -1,35 +1,107 @@
 package logger
 
-var defaultManager = NewManager()
+import (
+	"context"
+	"sync"
+)
+
+// defaultManager contains the manager used by the package-level functions
+var defaultManager = struct {
+	sync.RWMutex
+	m Manager
+}{
+	m: NewManager(),
+}
+
+// Default returns the default manager, used by the package-level
+// functions
+func Default() Manager {
+	defaultManager.RLock()
+	defer defaultManager.RUnlock()
+	return defaultManager.m
+}
+
+// SetDefault replaces the default manager by m, and returns the previous
+// one. Since a closed manager cannot be reused, it's the supported way to
+// keep using the package-level functions once the default manager has been
+// closed. The previous manager is neither closed nor detached.
+// A nil manager is replaced by a new one
+func SetDefault(m Manager) (previous Manager) {
+	if m == nil {
+		m = NewManager()
+	}
+	defaultManager.Lock()
+	defer defaultManager.Unlock()
+	previous, defaultManager.m = defaultManager.m, m
+	return previous
+}
 
 // AddGlobalData is used to add data that will be added to all logs
 func AddGlobalData(key string, value interface{}) {
-	defaultManager.AddGlobalData(key, value)
+	Default().AddGlobalData(key, value)
 }
 
 // RemoveGlobalData is used to remove data that are added to all logs
 func RemoveGlobalData(key string) {
-	defaultManager.RemoveGlobalData(key)
+	Default().RemoveGlobalData(key)
 }
 
 // Add adds a logger
-// returns ErrAlreadyExist if the logger has already been added
+// returns ErrAlreadyExist if the logger has already been added,
+// or ErrClosed if the default manager is closed
 func Add(l Logger) error {
-	return defaultManager.Add(l)
+	return Default().Add(l)
+}
+
+// AddShared adds a logger that is owned by someone else. The default
+// manager will never close it
+// returns ErrAlreadyExist if the logger has already been added,
+// or ErrClosed if the default manager is closed
+func AddShared(l Logger) error {
+	return Default().AddShared(l)
 }
 
 // Remove safely removes a logger
 // returns an Error struct if a logger could not be safely removed.
 // Upon errors the logger will be force removed from the manager
+// The logger is only closed if no other managers own it. If it is
+// not closed, a *SharedErr containing the other owners is returned
 func Remove(loggerID string) error {
-	return defaultManager.Remove(loggerID)
+	return Default().Remove(loggerID)
+}
+
+// Detach removes a logger without closing it
+func Detach(loggerID string) {
+	Default().Detach(loggerID)
+}
+
+// IsClosed returns whether the default manager has been closed
+func IsClosed() bool {
+	return Default().IsClosed()
 }
 
 // Close safely removes all the loggers
 // All submanagers will also be closed
 // returns a list of errors if a logger could not be safely removed.
 func Close() []error {
-	return defaultManager.Close()
+	return Default().Close()
+}
+
+// CloseContext safely removes all the loggers, and waits for them
+// to be closed until ctx is done.
+// All submanagers will also be closed
+// returns a list of errors if a logger could not be safely removed,
+// or didn't close in time.
+func CloseContext(ctx context.Context) []error {
+	return Default().CloseContext(ctx)
+}
+
+// Flush flushes the loggers implementing Flusher, and waits for them
+// until ctx is done.
+// returns a list of errors if a logger could not be flushed, or
+// didn't flush in time.
+func Flush(ctx context.Context) []error {
+	return Default().Flush(ctx)
 }
 
 // NewSubManager creates a new manager that can have its own loggers.
@@ -37,53 +109,128 @@ func Close() []error {
 // Calling a logging method on a submanager will trigger the same logging
 // method on the parent.
 func NewSubManager(tag string) Manager {
-	return defaultManager.NewSubManager(tag)
+	return Default().NewSubManager(tag)
 }
 
 // SetTag adds a tag to the logs
 func SetTag(tag string) {
-	defaultManager.SetTag(tag)
+	Default().SetTag(tag)
 }
 
 // Tag returns the tag of the manager
 func Tag() string {
-	return defaultManager.Tag()
+	return Default().Tag()
 }
 
 // FullTag returns the full tag (including parents) of the manager
 func FullTag() string {
-	return defaultManager.FullTag()
+	return Default().FullTag()
+}
+
+// SetContext attaches a context to the logs
+func SetContext(ctx context.Context) {
+	Default().SetContext(ctx)
+}
+
+// Context returns the context attached to the logs
+func Context() context.Context {
+	return Default().Context()
+}
+
+// OnSinkError sets the function called when a logger fails to write
+// an entry
+func OnSinkError(fn SinkErrorFunc) {
+	Default().OnSinkError(fn)
+}
+
+// SetClock sets the clock used to timestamp the entries
+func SetClock(clock Clock) {
+	Default().SetClock(clock)
+}
+
+// SetMessagePolicy sets the way the long and multi-line messages are
+// formatted for the loggers that don't implement EntryLogger
+func SetMessagePolicy(p MessagePolicy) {
+	Default().SetMessagePolicy(p)
+}
+
+// ClearMessagePolicy removes the message policy
+func ClearMessagePolicy() {
+	Default().ClearMessagePolicy()
+}
+
+// SetMinLevel sets the minimum level an entry must have to be logged
+func SetMinLevel(lvl Level) {
+	Default().SetMinLevel(lvl)
+}
+
+// ClearMinLevel removes the minimum level
+func ClearMinLevel() {
+	Default().ClearMinLevel()
+}
+
+// MinLevel returns the minimum level an entry must have to be logged
+func MinLevel() Level {
+	return Default().MinLevel()
+}
+
+// Loggers returns the loggers, sorted by ID
+func Loggers() []Logger {
+	return Default().Loggers()
+}
+
+// Children returns the submanagers, sorted by tag
+func Children() []Manager {
+	return Default().Children()
+}
+
+// Globals returns a copy of the global data
+func Globals() map[string]interface{} {
+	return Default().Globals()
+}
+
+// Walk calls fn for the default manager and all its submanagers,
+// depth first.
+// The walk stops as soon as fn returns false
+func Walk(fn func(Manager) bool) {
+	Default().Walk(fn)
+}
+
+// Dump returns a human-readable representation of the tree of
+// managers
+func Dump() string {
+	return Default().Dump()
 }
 
 // ID returns the manager's unique ID
 func ID() string {
-	return defaultManager.ID()
+	return Default().ID()
 }
 
 // Errorf logs an error message
 // Arguments are handled in the manner of fmt.Printf
 func Errorf(msg string, args ...interface{}) {
-	defaultManager.Errorf(msg, args...)
+	Default().Errorf(msg, args...)
 }
 
 // Error logs an error message
 // Arguments are handled in the manner of fmt.Println.
 func Error(args ...interface{}) {
-	defaultManager.Error(args...)
+	Default().Error(args...)
 }
 
 // Infof logs a message that may be helpful, but isn’t essential,
 // for troubleshooting
 // Arguments are handled in the manner of fmt.Printf
 func Infof(msg string, args ...interface{}) {
-	defaultManager.Infof(msg, args...)
+	Default().Infof(msg, args...)
 }
 
 // Info logs a message that may be helpful, but isn’t essential,
 // for troubleshooting
 // Arguments are handled in the manner of fmt.Println.
 func Info(args ...interface{}) {
-	defaultManager.Info(args...)
+	Default().Info(args...)
 }
 
 // Debugf logs a message that is intended for use in a development
@@ -91,7 +238,7 @@ func Info(args ...interface{}) {
 // software
 // Arguments are handled in the manner of fmt.Printf
 func Debugf(msg string, args ...interface{}) {
-	defaultManager.Debugf(msg, args...)
+	Default().Debugf(msg, args...)
 }
 
 // Debug logs a message that is intended for use in a development
@@ -99,17 +246,17 @@ func Debugf(msg string, args ...interface{}) {
 // software
 // Arguments are handled in the manner of fmt.Println.
 func Debug(args ...interface{}) {
-	defaultManager.Debug(args...)
+	Default().Debug(args...)
 }
 
 // Logf logs a message that might result a failure
 // Arguments are handled in the manner of fmt.Printf
 func Logf(msg string, args ...interface{}) {
-	defaultManager.Logf(msg, args...)
+	Default().Logf(msg, args...)
 }
 
 // Log logs a message that might result a failure
 // Arguments are handled in the manner of fmt.Println.
 func Log(args ...interface{}) {
-	defaultManager.Log(args...)
+	Default().Log(args...)
 }
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,7 +9,7 @@ import (
 )
 
 func TestDefaultManager(t *testing.T) {
-	m := defaultManager.(*DefaultManager)
+	m := Default().(*DefaultManager)
 	l := NewSliceLogger().(*SliceLogger)
 	require.NoError(t, Add(l))
 	require.Len(t, m.loggers, 1)
@@ -38,6 +39,13 @@ func TestDefaultManager(t *testing.T) {
 		assert.NotEmpty(t, ID())
 	})
 
+	t.Run("MinLevel", func(t *testing.T) {
+		SetMinLevel(LevelError)
+		defer ClearMinLevel()
+		assert.Equal(t, LevelError, MinLevel())
+		require.True(t, m.hasMinLevel)
+	})
+
 	t.Run("Error", func(t *testing.T) {
 		defer l.clear()
 		Error("a", "b")
@@ -104,17 +112,41 @@ func TestDefaultManager(t *testing.T) {
 }
 
 func TestDefaultManagerClose(t *testing.T) {
-	m := defaultManager.(*DefaultManager)
+	m := Default().(*DefaultManager)
 	l := NewSliceLogger().(*SliceLogger)
 	require.NoError(t, Add(l))
 	require.Len(t, m.loggers, 1)
 
 	require.Len(t, Close(), 0)
 	require.Len(t, m.loggers, 0)
+	assert.True(t, IsClosed())
+	assert.Equal(t, ErrClosed, Add(l))
+
+	previous := SetDefault(nil)
+	assert.Equal(t, m, previous, "the closed manager should be returned")
+	assert.False(t, IsClosed(), "a new default manager should have been set")
+	require.NoError(t, Add(l))
+	require.Len(t, Close(), 0)
+	SetDefault(nil)
+}
+
+func TestSetDefault(t *testing.T) {
+	m := NewManager()
+	l := NewSliceLogger().(*SliceLogger)
+	require.NoError(t, m.Add(l))
+
+	previous := SetDefault(m)
+	defer SetDefault(previous)
+
+	assert.Equal(t, m, Default())
+	assert.Equal(t, m, FromContext(context.Background()))
+	Log("foo")
+	require.Len(t, l.data, 1, "the entry should have been sent to the new default manager")
+	assert.Equal(t, "foo\n", l.data[0])
 }
 
 func TestDefaultManagerSub(t *testing.T) {
-	m := defaultManager.(*DefaultManager)
+	m := Default().(*DefaultManager)
 	SetTag("[parent]")
 	l := NewSliceLogger().(*SliceLogger)
 	require.NoError(t, Add(l))
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ECSVersion is the version of the Elastic Common Schema used by
+// ECSFormatter
+const ECSVersion = "1.6.0"
+
+// we make sure ECSFormatter implements Formatter
+var _ Formatter = (*ECSFormatter)(nil)
+
+// NewECSFormatter creates and returns a formatter that encodes entries
+// as JSON documents following the Elastic Common Schema
+func NewECSFormatter() Formatter {
+	return &ECSFormatter{
+		now: time.Now,
+	}
+}
+
+// ECSFormatter is a formatter that encodes entries as JSON documents
+// following the Elastic Common Schema (ECS), one document per line.
+// - The full tag of the manager is set in log.logger
+// - The sequence number of the entry is set in event.sequence
+// - Errors are set in error.*. If there are more than one error, the
+// first one (by key) is used, and the others are added to the labels
+// - Strings, booleans and numbers are set in labels.*
+// - Any other global is added to the document as a custom field
+type ECSFormatter struct {
+	// now is used for the entries that don't have a time
+	now Clock
+}
+
+// Format returns the ECS representation of an entry
+func (f *ECSFormatter) Format(e *Entry) ([]byte, error) {
+	doc := map[string]interface{}{
+		"@timestamp":  entryTime(e, f.now).UTC().Format(time.RFC3339Nano),
+		"log.level":   strings.ToLower(e.Level.String()),
+		"message":     e.Message,
+		"ecs.version": ECSVersion,
+	}
+	if e.Tag != "" {
+		doc["log.logger"] = e.Tag
+	}
+	if e.Seq != 0 {
+		doc["event.sequence"] = e.Seq
+	}
+
+	labels := map[string]interface{}{}
+	var errorSet bool
+	fields := e.Data()
+	for _, k := range sortedKeys(fields) {
+		switch v := fields[k].(type) {
+		case error:
+			if errorSet {
+				labels[k] = v.Error()
+				continue
+			}
+			errorSet = true
+			doc["error"] = ecsError(v)
+		case string, bool,
+			int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64,
+			float32, float64:
+			labels[k] = v
+		default:
+			if _, reserved := doc[k]; reserved || k == "labels" || k == "error" {
+				labels[k] = fmt.Sprintf("%+v", v)
+				continue
+			}
+			doc[k] = encodableValue(k, v)
+		}
+	}
+	if len(labels) > 0 {
+		doc["labels"] = labels
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// ecsError returns the error.* fields of an error
+func ecsError(err error) map[string]interface{} {
+	fields := map[string]interface{}{
+		"message": err.Error(),
+		"type":    fmt.Sprintf("%T", err),
+	}
+	// errors implementing fmt.Formatter (like the ones of github.com/pkg/errors)
+	// print their stack trace with %+v
+	if _, ok := err.(fmt.Formatter); ok {
+		fields["stack_trace"] = fmt.Sprintf("%+v", err)
+	}
+	return fields
+}
+
+// sortedKeys returns the keys of a map, sorted alphabetically
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECSFormatter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2019, 5, 4, 10, 30, 0, 0, time.UTC)
+	newFormatter := func() Formatter {
+		f := NewECSFormatter().(*ECSFormatter)
+		f.now = func() time.Time { return now }
+		return f
+	}
+
+	t.Run("entry without globals", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := newFormatter().Format(&Entry{
+			Level:   LevelInfo,
+			Message: "message",
+		})
+		require.NoError(t, err)
+		expected := `{
+			"@timestamp": "2019-05-04T10:30:00Z",
+			"ecs.version": "1.6.0",
+			"log.level": "info",
+			"message": "message"
+		}`
+		assert.JSONEq(t, expected, string(data))
+		assert.Equal(t, byte('\n'), data[len(data)-1], "missing new line")
+	})
+
+	t.Run("the time of the entry is used", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := newFormatter().Format(&Entry{
+			Time:    time.Date(2021, 2, 3, 4, 5, 6, 7, time.FixedZone("UTC+1", 3600)),
+			Level:   LevelInfo,
+			Message: "message",
+		})
+		require.NoError(t, err)
+		doc := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(data, &doc))
+		assert.Equal(t, "2021-02-03T03:05:06.000000007Z", doc["@timestamp"])
+	})
+
+	t.Run("the sequence number is set", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := newFormatter().Format(&Entry{Seq: 42, Level: LevelInfo, Message: "message"})
+		require.NoError(t, err)
+		doc := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(data, &doc))
+		assert.Equal(t, float64(42), doc["event.sequence"])
+	})
+
+	t.Run("globals are mapped to ECS fields", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		m := NewManagerWithTag("[app]")
+		require.NoError(t, m.Add(NewWriterLogger(&buf, newFormatter())))
+		m.AddGlobalData("user", "john")
+		m.AddGlobalData("count", 2)
+		m.AddGlobalData("request", map[string]string{"method": "GET"})
+		m.AddGlobalData("message", []int{1})
+		m.AddGlobalData("a_error", errors.New("first"))
+		m.AddGlobalData("b_error", errors.New("second"))
+
+		m.NewSubManager("[sub]").Error("could not process")
+
+		doc := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		assert.Equal(t, "error", doc["log.level"])
+		assert.Equal(t, "could not process", doc["message"])
+		assert.Equal(t, "[app][sub]", doc["log.logger"])
+		assert.Equal(t, map[string]interface{}{"method": "GET"}, doc["request"])
+		assert.Equal(t, map[string]interface{}{
+			"user":    "john",
+			"count":   float64(2),
+			"b_error": "second",
+			"message": "[1]",
+		}, doc["labels"])
+
+		ecsErr, ok := doc["error"].(map[string]interface{})
+		require.True(t, ok, "error.* should be set")
+		assert.Equal(t, "first", ecsErr["message"])
+		assert.NotEmpty(t, ecsErr["type"])
+	})
+}
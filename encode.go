@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// internalErrors contains the function called when the library fails
+// without being able to return an error
+var internalErrors = struct {
+	sync.RWMutex
+	handler func(err error)
+}{
+	handler: func(err error) {
+		log.Print("logger: " + err.Error())
+	},
+}
+
+// SetInternalErrorHandler sets the function called when the library
+// fails without being able to return an error, like when a global
+// cannot be encoded to JSON.
+// Defaults to printing the errors on stderr. A nil handler drops them
+func SetInternalErrorHandler(fn func(err error)) {
+	internalErrors.Lock()
+	defer internalErrors.Unlock()
+	internalErrors.handler = fn
+}
+
+// reportInternalError sends an error to the internal error handler
+func reportInternalError(err error) {
+	internalErrors.RLock()
+	fn := internalErrors.handler
+	internalErrors.RUnlock()
+
+	if fn != nil {
+		fn(err)
+	}
+}
+
+// unencodablePlaceholder returns the value printed in place of a value
+// that cannot be encoded to JSON
+func unencodablePlaceholder(v interface{}) string {
+	return fmt.Sprintf("[unencodable %T]", v)
+}
+
+// encodeData returns the JSON representation of the globals and fields
+// of an entry, or nil if there are none.
+// The values that cannot be encoded, like channels, functions, or cyclic
+// structures, are replaced by a placeholder, and reported to the
+// internal error handler
+func encodeData(data map[string]interface{}) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	// The values are only checked one by one if encoding them together
+	// fails
+	cyclic := false
+	for _, v := range data {
+		if hasCycle(v) {
+			cyclic = true
+			break
+		}
+	}
+	if !cyclic {
+		if encoded, err := json.Marshal(data); err == nil {
+			return encoded
+		}
+	}
+
+	safe := make(map[string]interface{}, len(data))
+	for _, k := range sortedKeys(data) {
+		safe[k] = encodableValue(k, data[k])
+	}
+
+	encoded, err := json.Marshal(safe)
+	if err != nil {
+		// Can't happen since all the values have been checked
+		reportInternalError(errors.Wrap(err, "could not encode the globals to JSON"))
+		return nil
+	}
+	return encoded
+}
+
+// encodableValue returns v, or a placeholder if v cannot be encoded to
+// JSON. k is the key of the value, used to report the error to the
+// internal error handler
+func encodableValue(k string, v interface{}) interface{} {
+	if hasCycle(v) {
+		reportInternalError(errors.Errorf("could not encode %q to JSON: cycle detected", k))
+		return unencodablePlaceholder(v)
+	}
+	if _, err := json.Marshal(v); err != nil {
+		reportInternalError(errors.Wrapf(err, "could not encode %q to JSON", k))
+		return unencodablePlaceholder(v)
+	}
+	return v
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// visit identifies a value already being encoded
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// hasCycle returns whether v contains a reference to itself, which
+// cannot be encoded to JSON
+func hasCycle(v interface{}) bool {
+	switch v.(type) {
+	case nil, string, bool,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return false
+	}
+	return walkCycle(reflect.ValueOf(v), map[visit]bool{})
+}
+
+// walkCycle returns whether v contains one of the references of path,
+// or a reference to itself
+func walkCycle(v reflect.Value, path map[visit]bool) bool {
+	if !v.IsValid() {
+		return false
+	}
+	// the values encoding themselves are not walked by encoding/json
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() || (v.Kind() == reflect.Slice && (v.Len() == 0 || t.Elem().Kind() == reflect.Uint8)) {
+			return false
+		}
+		key := visit{ptr: v.Pointer(), typ: t}
+		if v.Kind() == reflect.Slice {
+			key.len = v.Len()
+		}
+		if path[key] {
+			return true
+		}
+		path[key] = true
+		defer delete(path, key)
+
+		switch v.Kind() {
+		case reflect.Ptr:
+			return walkCycle(v.Elem(), path)
+		case reflect.Map:
+			iter := v.MapRange()
+			for iter.Next() {
+				if walkCycle(iter.Value(), path) {
+					return true
+				}
+			}
+			return false
+		default:
+			return walkElems(v, path)
+		}
+	case reflect.Array:
+		return walkElems(v, path)
+	case reflect.Interface:
+		return walkCycle(v.Elem(), path)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := t.Field(i)
+			// unexported and ignored fields are not encoded
+			if (f.PkgPath != "" && !f.Anonymous) || f.Tag.Get("json") == "-" {
+				continue
+			}
+			if walkCycle(v.Field(i), path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// walkElems returns whether one of the elements of an array or a slice
+// contains a cycle
+func walkElems(v reflect.Value, path map[visit]bool) bool {
+	for i := 0; i < v.Len(); i++ {
+		if walkCycle(v.Index(i), path) {
+			return true
+		}
+	}
+	return false
+}
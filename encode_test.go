@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// node is a structure that can reference itself
+type node struct {
+	Name     string
+	Next     *node
+	Children []*node
+	parent   *node
+}
+
+// This test changes the internal error handler, so it cannot run in
+// parallel
+func TestEncodeData(t *testing.T) {
+	var mu sync.Mutex
+	var reported []string
+	internalErrors.RLock()
+	defer SetInternalErrorHandler(internalErrors.handler)
+	internalErrors.RUnlock()
+	SetInternalErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err.Error())
+	})
+	reset := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		errs := reported
+		reported = nil
+		return errs
+	}
+
+	cyclic := &node{Name: "a"}
+	cyclic.Next = cyclic
+	cyclicMap := map[string]interface{}{}
+	cyclicMap["self"] = cyclicMap
+	shared := &node{Name: "shared"}
+	notCyclic := &node{Name: "root", Next: shared, Children: []*node{shared, shared}}
+	notCyclic.parent = notCyclic
+
+	testCases := []struct {
+		description string
+		value       interface{}
+		expected    string
+		reported    bool
+	}{
+		{description: "scalar", value: 42, expected: `{"k":42}`},
+		{description: "shared references", value: notCyclic, expected: `{"k":{"Name":"root","Next":{"Name":"shared","Next":null,"Children":null},"Children":[{"Name":"shared","Next":null,"Children":null},{"Name":"shared","Next":null,"Children":null}]}}`},
+		{description: "marshaler", value: time.Date(2019, 5, 4, 10, 30, 0, 0, time.UTC), expected: `{"k":"2019-05-04T10:30:00Z"}`},
+		{description: "channel", value: make(chan int), expected: `{"k":"[unencodable chan int]"}`, reported: true},
+		{description: "function", value: func() {}, expected: `{"k":"[unencodable func()]"}`, reported: true},
+		{description: "cyclic pointer", value: cyclic, expected: `{"k":"[unencodable *logger.node]"}`, reported: true},
+		{description: "cyclic map", value: cyclicMap, expected: `{"k":"[unencodable map[string]interface {}]"}`, reported: true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			encoded := encodeData(map[string]interface{}{"k": tc.value})
+			assert.Equal(t, tc.expected, string(encoded))
+
+			errs := reset()
+			if !tc.reported {
+				assert.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1, "the error should have been reported")
+			assert.Contains(t, errs[0], `could not encode "k" to JSON`)
+		})
+	}
+
+	t.Run("the other values are kept", func(t *testing.T) {
+		encoded := encodeData(map[string]interface{}{"a": 1, "b": make(chan int), "c": "value"})
+		assert.Equal(t, `{"a":1,"b":"[unencodable chan int]","c":"value"}`, string(encoded))
+		assert.Len(t, reset(), 1)
+	})
+
+	t.Run("managers don't panic", func(t *testing.T) {
+		m := NewManagerWithTag("[app]")
+		l := &SliceLogger{}
+		require.NoError(t, m.Add(l))
+		m.AddGlobalData("ch", make(chan int))
+		m.AddGlobalData("user", "john")
+
+		m.Error("a")
+		m.Info("b", Any("fn", func() {}))
+		m.Logf("%s %s", "c", "d")
+
+		require.Len(t, l.data, 3, "no logs added")
+		assert.Equal(t, "[ERROR][app] a\n{\"ch\":\"[unencodable chan int]\",\"user\":\"john\"}\n", l.data[0])
+		assert.Equal(t, "[INFO][app] b\n{\"ch\":\"[unencodable chan int]\",\"fn\":\"[unencodable func()]\",\"user\":\"john\"}\n", l.data[1])
+		assert.Equal(t, "[app] c d\n{\"ch\":\"[unencodable chan int]\",\"user\":\"john\"}\n", l.data[2])
+		assert.NotEmpty(t, reset())
+	})
+
+	t.Run("formatters don't fail", func(t *testing.T) {
+		e := &Entry{
+			Level:   LevelInfo,
+			Message: "message",
+			Globals: map[string]interface{}{"chan": make(chan int)},
+			Fields:  map[string]interface{}{"cyclic": cyclic},
+		}
+
+		data, err := NewECSFormatter().Format(e)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"chan":"[unencodable chan int]"`)
+		assert.Contains(t, string(data), `"cyclic":"[unencodable *logger.node]"`)
+		assert.Len(t, reset(), 2)
+
+		console := NewConsoleFormatter(&strings.Builder{}).(*ConsoleFormatter)
+		data, err = console.Format(e)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `chan: "[unencodable chan int]"`)
+		assert.Contains(t, string(data), `cyclic: "[unencodable *logger.node]"`)
+		assert.Len(t, reset(), 2)
+
+		tmpl, err := NewTemplateFormatter("{msg} {fields}")
+		require.NoError(t, err)
+		data, err = tmpl.Format(e)
+		require.NoError(t, err)
+		assert.Equal(t, `message {"chan":"[unencodable chan int]","cyclic":"[unencodable *logger.node]"}`+"\n", string(data))
+		assert.Len(t, reset(), 2)
+	})
+}
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// sequence is the sequence number of the last entry created
+var sequence uint64
+
+// nextSequence returns the sequence number of a new entry
+func nextSequence() uint64 {
+	return atomic.AddUint64(&sequence, 1)
+}
+
+// Clock is a function that returns the current time
+type Clock func() time.Time
+
+// Entry represents a single log entry, as built by a Manager
+type Entry struct {
+	// Time is the time at which the entry has been created
+	Time time.Time
+
+	// Seq is a number that is unique to the entry within the process.
+	// Entries created later have a greater sequence number, which can be
+	// used to restore the order of entries that have been reordered
+	Seq uint64
+
+	// Level is the level the entry has been logged at
+	Level Level
+
+	// Message is the message of the entry, without any tag, globals or
+	// trailing new line
+	Message string
+
+	// Tag is the full tag (including parents) of the manager that created
+	// the entry
+	Tag string
+
+	// Tags contains the non-empty tags of the manager that created the
+	// entry and of its parents, starting with the root manager
+	Tags []string
+
+	// Globals contains the global data of the manager that created the
+	// entry, including the data of its parents.
+	// The map must not be modified
+	Globals map[string]interface{}
+
+	// Fields contains the values of the fields passed with the message.
+	// The map must not be modified
+	Fields map[string]interface{}
+
+	// Context is the context attached to the manager that created the
+	// entry
+	Context context.Context
+
+	// unreported is set for the entries logged by a SinkErrorFunc, whose
+	// write errors must not be reported again
+	unreported bool
+}
+
+// Data returns the globals and the fields of the entry. The fields take
+// precedence over the globals using the same key.
+// The returned map must not be modified
+func (e *Entry) Data() map[string]interface{} {
+	if len(e.Fields) == 0 {
+		return e.Globals
+	}
+	if len(e.Globals) == 0 {
+		return e.Fields
+	}
+
+	data := make(map[string]interface{}, len(e.Globals)+len(e.Fields))
+	for k, v := range e.Globals {
+		data[k] = v
+	}
+	for k, v := range e.Fields {
+		data[k] = v
+	}
+	return data
+}
+
+// EntryLogger is an optional interface that can be implemented by a
+// Logger that wants to receive structured entries instead of formatted
+// messages.
+// When a logger implements EntryLogger, the manager calls LogEntry instead
+// of Error, Info, Debug or Log
+type EntryLogger interface {
+	Logger
+
+	// LogEntry logs a structured entry
+	// returns an error if the entry could not be written
+	LogEntry(e *Entry) error
+}
+
+// NewEntry creates and returns an entry of the given level, logged now,
+// for a message received by one of the string methods of Logger
+func NewEntry(msg string, lvl Level) *Entry {
+	return &Entry{
+		Time:    time.Now(),
+		Level:   lvl,
+		Message: strings.TrimSuffix(msg, "\n"),
+	}
+}
+
+// WriteLevel sends a message to l as an entry of the given level. It's
+// meant to implement the string methods of Logger on top of LogEntry.
+// Since those methods have no way to report errors, the error returned
+// by LogEntry is dropped
+func WriteLevel(l EntryLogger, msg string, lvl Level) {
+	_ = l.LogEntry(NewEntry(msg, lvl)) //nolint:errcheck
+}
+
+// writeEntry sends an entry to the given logger, using the structured
+// path if the logger supports it. msg returns the formatted version of
+// the entry, used for loggers that only accept strings
+func writeEntry(l Logger, e *Entry, msg func() string) error {
+	if el, ok := l.(EntryLogger); ok {
+		return el.LogEntry(e)
+	}
+
+	switch e.Level {
+	case LevelError:
+		l.Error(msg())
+	case LevelInfo:
+		l.Info(msg())
+	case LevelDebug:
+		l.Debug(msg())
+	default:
+		l.Log(msg())
+	}
+	return nil
+}
+
+// entryTime returns the time of an entry, or the current time if the
+// entry doesn't have one
+func entryTime(e *Entry, now Clock) time.Time {
+	if e.Time.IsZero() {
+		return now()
+	}
+	return e.Time
+}
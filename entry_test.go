@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// entrySliceLogger is a SliceLogger that receives structured entries
+type entrySliceLogger struct {
+	SliceLogger
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+func (l *entrySliceLogger) LogEntry(e *Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, e)
+	return nil
+}
+
+func TestEntryLogger(t *testing.T) {
+	t.Parallel()
+
+	m := NewManagerWithTag("[parent]")
+	l := &entrySliceLogger{}
+	require.NoError(t, m.Add(l))
+	m.AddGlobalData("key", "value")
+
+	sm := m.NewSubManager("[child]")
+	sm.Errorf("%s %s", "a", "b")
+
+	require.Empty(t, l.data, "the string methods should not have been called")
+	require.Len(t, l.entries, 1, "no entries added")
+	e := l.entries[0]
+	assert.Equal(t, LevelError, e.Level)
+	assert.Equal(t, "a b", e.Message)
+	assert.Equal(t, "[parent][child]", e.Tag)
+	assert.Equal(t, []string{"[parent]", "[child]"}, e.Tags)
+	assert.Equal(t, map[string]interface{}{"key": "value"}, e.Globals)
+	assert.Equal(t, sm.Context(), e.Context)
+}
+
+func TestWriteLevel(t *testing.T) {
+	t.Parallel()
+
+	l := &entrySliceLogger{}
+	before := time.Now()
+	WriteLevel(l, "message\n", LevelInfo)
+
+	require.Len(t, l.entries, 1, "no entries added")
+	e := l.entries[0]
+	assert.Equal(t, LevelInfo, e.Level)
+	assert.Equal(t, "message", e.Message, "the trailing new line should have been removed")
+	assert.False(t, e.Time.Before(before), "the entry should have a time")
+}
+
+func TestEntryTimeAndSeq(t *testing.T) {
+	t.Parallel()
+
+	parentTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	childTime := parentTime.Add(time.Hour)
+
+	m := NewManager()
+	l := &entrySliceLogger{}
+	require.NoError(t, m.Add(l))
+
+	m.Info("no clock")
+	require.Len(t, l.entries, 1, "no entries added")
+	assert.False(t, l.entries[0].Time.IsZero(), "time.Now should have been used")
+
+	m.SetClock(func() time.Time { return parentTime })
+	sm := m.NewSubManager("[child]")
+	sm.Info("parent clock")
+	require.Len(t, l.entries, 2, "no entries added")
+	assert.Equal(t, parentTime, l.entries[1].Time, "the clock of the parent should be used")
+
+	sm.SetClock(func() time.Time { return childTime })
+	sm.Info("own clock")
+	require.Len(t, l.entries, 3, "no entries added")
+	assert.Equal(t, childTime, l.entries[2].Time, "the clock of the manager should be used")
+
+	sm.SetClock(nil)
+	sm.Info("parent clock again")
+	require.Len(t, l.entries, 4, "no entries added")
+	assert.Equal(t, parentTime, l.entries[3].Time, "the clock of the parent should be used")
+
+	for i := 1; i < len(l.entries); i++ {
+		assert.True(t, l.entries[i].Seq > l.entries[i-1].Seq, "sequence numbers should increase")
+	}
+}
+
+func TestEntrySeqConcurrent(t *testing.T) {
+	t.Parallel()
+
+	const n = 100
+	seqs := make(chan uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seqs <- nextSequence()
+		}()
+	}
+	wg.Wait()
+	close(seqs)
+
+	seen := map[uint64]bool{}
+	for seq := range seqs {
+		assert.False(t, seen[seq], "sequence numbers should be unique")
+		seen[seq] = true
+	}
+	assert.Len(t, seen, n)
+}
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// we make sure FailoverLogger implements EntryLogger and Flusher
+var (
+	_ EntryLogger = (*FailoverLogger)(nil)
+	_ Flusher     = (*FailoverLogger)(nil)
+)
+
+// NewFailoverLogger creates and returns a logger that sends the entries
+// to primary, and to the fallbacks, in order, if primary fails.
+// Only the loggers implementing EntryLogger can report a failure
+func NewFailoverLogger(primary Logger, fallbacks ...Logger) *FailoverLogger {
+	return &FailoverLogger{
+		id:      "failover-logger:" + uuid.New().String(),
+		loggers: append([]Logger{primary}, fallbacks...),
+	}
+}
+
+// FailoverLogger is a go-routine safe logger that tries a list of loggers
+// until one of them succeeds, like a file then stderr
+type FailoverLogger struct {
+	// OnError is called every time one of the loggers fails, even if
+	// a fallback succeeded.
+	// Must be set before the logger is used
+	OnError func(l Logger, e *Entry, err error)
+
+	id      string
+	loggers []Logger
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// ID returns the logger's unique ID
+func (l *FailoverLogger) ID() string {
+	return l.id
+}
+
+// Close closes all the loggers
+// the logger may not be reusable after being closed
+func (l *FailoverLogger) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	l.mu.Unlock()
+
+	var errs []string
+	for _, sink := range l.loggers {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, sink.ID()+": "+err.Error())
+		}
+	}
+	return combineErrors("could not close the loggers", errs)
+}
+
+// IsClosed returns wether the logger is closed or not
+func (l *FailoverLogger) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// Flush flushes the loggers implementing Flusher
+func (l *FailoverLogger) Flush() error {
+	var errs []string
+	for _, sink := range l.loggers {
+		f, ok := sink.(Flusher)
+		if !ok {
+			continue
+		}
+		if err := f.Flush(); err != nil {
+			errs = append(errs, sink.ID()+": "+err.Error())
+		}
+	}
+	return combineErrors("could not flush the loggers", errs)
+}
+
+// LogEntry sends the entry to the first logger that accepts it.
+// Returns an error if all the loggers failed
+func (l *FailoverLogger) LogEntry(e *Entry) error {
+	msg := textFunc(e)
+	var errs []string
+	for _, sink := range l.loggers {
+		// The loggers may have been closed by someone else
+		if sink.IsClosed() {
+			continue
+		}
+
+		err := writeEntry(sink, e, msg)
+		if err == nil {
+			return nil
+		}
+		if l.OnError != nil {
+			l.OnError(sink, e, err)
+		}
+		errs = append(errs, sink.ID()+": "+err.Error())
+	}
+
+	if len(errs) == 0 {
+		return errors.New("all the loggers are closed")
+	}
+	return combineErrors("all the loggers failed", errs)
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (l *FailoverLogger) Error(msg string) {
+	WriteLevel(l, msg, LevelError)
+}
+
+// Info logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Println.
+func (l *FailoverLogger) Info(msg string) {
+	WriteLevel(l, msg, LevelInfo)
+}
+
+// Debug logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Println.
+func (l *FailoverLogger) Debug(msg string) {
+	WriteLevel(l, msg, LevelDebug)
+}
+
+// Log logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Println.
+func (l *FailoverLogger) Log(msg string) {
+	WriteLevel(l, msg, LevelDefault)
+}
+
+// entryText returns the text representation of an entry, in the same
+// format as the one used by the managers
+func entryText(e *Entry) string {
+	s := e.Message + "\n"
+	if e.Tag != "" {
+		s = e.Tag + " " + s
+	}
+	if encoded := encodeData(e.Data()); len(encoded) > 0 {
+		s += string(encoded) + "\n"
+	}
+	return s
+}
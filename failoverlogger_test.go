@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingLogger is a SliceLogger which structured path always fails
+type failingLogger struct {
+	SliceLogger
+	err error
+}
+
+func (l *failingLogger) LogEntry(e *Entry) error {
+	return l.err
+}
+
+func TestFailoverLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the primary logger is used when it works", func(t *testing.T) {
+		t.Parallel()
+
+		primary := &entrySliceLogger{}
+		fallback := &entrySliceLogger{SliceLogger: SliceLogger{id: "fallback"}}
+		l := NewFailoverLogger(primary, fallback)
+
+		require.NoError(t, l.LogEntry(&Entry{Level: LevelInfo, Message: "message"}))
+		assert.Len(t, primary.entries, 1)
+		assert.Empty(t, fallback.entries)
+	})
+
+	t.Run("the fallbacks are used in order", func(t *testing.T) {
+		t.Parallel()
+
+		primary := &failingLogger{err: errors.New("disk full")}
+		second := &failingLogger{SliceLogger: SliceLogger{id: "second"}, err: errors.New("endpoint down")}
+		third := &SliceLogger{id: "third"}
+		l := NewFailoverLogger(primary, second, third)
+		var failed []string
+		l.OnError = func(sink Logger, e *Entry, err error) {
+			failed = append(failed, sink.ID()+": "+err.Error())
+		}
+
+		l.Error("message")
+		assert.Equal(t, []string{"slice-logger: disk full", "second: endpoint down"}, failed)
+		require.Len(t, third.data, 1, "the string logger should have been used")
+		assert.Equal(t, "[ERROR]message\n", third.data[0])
+	})
+
+	t.Run("entries are formatted for string loggers", func(t *testing.T) {
+		t.Parallel()
+
+		fallback := &SliceLogger{}
+		l := NewFailoverLogger(&failingLogger{SliceLogger: SliceLogger{id: "primary"}, err: errors.New("failed")}, fallback)
+		require.NoError(t, l.LogEntry(&Entry{
+			Level:   LevelInfo,
+			Message: "message",
+			Tag:     "[app]",
+			Globals: map[string]interface{}{"key": "value"},
+		}))
+		require.Len(t, fallback.data, 1)
+		assert.Equal(t, "[INFO][app] message\n{\"key\":\"value\"}\n", fallback.data[0])
+	})
+
+	t.Run("an error is returned if all the loggers fail", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewFailoverLogger(
+			&failingLogger{err: errors.New("disk full")},
+			&failingLogger{SliceLogger: SliceLogger{id: "second"}, err: errors.New("endpoint down")},
+		)
+		err := l.LogEntry(&Entry{Level: LevelInfo, Message: "message"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "disk full")
+		assert.Contains(t, err.Error(), "endpoint down")
+	})
+
+	t.Run("closed loggers are skipped", func(t *testing.T) {
+		t.Parallel()
+
+		primary := &entrySliceLogger{}
+		fallback := &entrySliceLogger{SliceLogger: SliceLogger{id: "fallback"}}
+		l := NewFailoverLogger(primary, fallback)
+		require.NoError(t, primary.Close())
+
+		require.NoError(t, l.LogEntry(&Entry{Level: LevelInfo, Message: "message"}))
+		assert.Empty(t, primary.entries)
+		assert.Len(t, fallback.entries, 1)
+
+		require.NoError(t, l.Close())
+		assert.True(t, l.IsClosed())
+		assert.True(t, fallback.IsClosed())
+		assert.Error(t, l.LogEntry(&Entry{Level: LevelInfo, Message: "message"}))
+	})
+
+	t.Run("Flush flushes the loggers", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		flusher := newFlushSliceLogger("flusher")
+		l := NewFailoverLogger(NewWriterLogger(&buf, NewECSFormatter()), flusher)
+		require.NoError(t, l.Flush())
+		assert.Len(t, flusher.flushed, 1)
+
+		flusher.flushErr = errors.New("disk full")
+		assert.Error(t, l.Flush())
+	})
+}
@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// funcLogger is a go-routine safe logger that calls a function for every
+// entry
+type funcLogger struct {
+	SliceLogger
+	fn func(e *Entry)
+}
+
+func (l *funcLogger) LogEntry(e *Entry) error {
+	l.fn(e)
+	return nil
+}
+
+func TestManagerFanOut(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loggers can call the manager", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		l := &funcLogger{SliceLogger: SliceLogger{id: "remove-itself"}}
+		l.fn = func(e *Entry) {
+			require.NoError(t, m.Remove(l.ID()))
+			m.Info("sent to the remaining loggers")
+		}
+		require.NoError(t, m.Add(l))
+		other := &entrySliceLogger{}
+		require.NoError(t, m.Add(other))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			m.Info("message")
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("the manager deadlocked")
+		}
+
+		assert.Len(t, m.Loggers(), 1)
+		assert.Len(t, other.entries, 2)
+	})
+
+	t.Run("slow loggers don't block the manager", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		sm := m.NewSubManager("[sub]")
+		release := make(chan struct{})
+		defer close(release)
+		started := make(chan struct{})
+		l := &funcLogger{SliceLogger: SliceLogger{id: "slow"}, fn: func(e *Entry) {
+			close(started)
+			<-release
+		}}
+		require.NoError(t, m.Add(l))
+
+		go sm.Info("message")
+		<-started
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.NoError(t, m.Add(&entrySliceLogger{}))
+			assert.NoError(t, sm.Add(&entrySliceLogger{}))
+			m.AddGlobalData("key", "value")
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("the manager is blocked by a logger")
+		}
+	})
+
+	t.Run("Loggers returns a copy", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		require.NoError(t, m.Add(&SliceLogger{id: "b"}))
+		require.NoError(t, m.Add(&SliceLogger{id: "a"}))
+
+		loggers := m.Loggers()
+		loggers[0] = nil
+		require.Len(t, m.Loggers(), 2)
+		assert.Equal(t, "a", m.Loggers()[0].ID())
+	})
+}
+
+func TestManagerStress(t *testing.T) {
+	t.Parallel()
+
+	const workers = 8
+	const iterations = 200
+
+	var received int64
+	newLogger := func(id string) Logger {
+		return &funcLogger{
+			SliceLogger: SliceLogger{id: id},
+			fn: func(e *Entry) {
+				atomic.AddInt64(&received, 1)
+			},
+		}
+	}
+
+	m := NewManagerWithTag("[root]")
+	require.NoError(t, m.Add(newLogger("root")))
+	// a real logger, shared by all the submanagers and closed while
+	// they are using it
+	shared := NewSliceLogger()
+	require.NoError(t, m.Add(shared))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			sm := m.NewSubManager(fmt.Sprintf("[worker%d]", w))
+			assert.NoError(t, sm.AddShared(shared))
+			for i := 0; i < iterations; i++ {
+				if w == 0 && i == iterations/2 {
+					assert.NoError(t, shared.Close())
+				}
+				id := fmt.Sprintf("logger-%d-%d", w, i)
+				assert.NoError(t, sm.Add(newLogger(id)))
+				sm.Info("message", Int("i", i))
+				m.Debugf("from %d", w)
+				sm.AddGlobalData("i", i)
+				assert.NoError(t, sm.Remove(id))
+
+				// we regularly replace the submanager
+				if i%50 == 0 {
+					assert.Empty(t, sm.Close())
+					sm = m.NewSubManager(fmt.Sprintf("[worker%d]", w))
+					assert.NoError(t, sm.AddShared(shared))
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// every call logged at least through the root logger
+	assert.True(t, atomic.LoadInt64(&received) >= workers*iterations*2)
+	assert.Empty(t, m.Close())
+	assert.Empty(t, m.Loggers())
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"time"
+)
+
+// ErrorKey is the key used by ErrorField
+const ErrorKey = "error"
+
+// Field is a key/value pair attached to a single entry.
+// Fields can be passed to the logging methods of a Manager alongside
+// the other arguments, and are added to the data of the entry instead of
+// being printed in its message:
+//
+//	m.Error("could not save the user", logger.ErrorField(err), logger.String("user_id", id))
+type Field struct {
+	// Key is the name of the field
+	Key string
+
+	value interface{}
+	lazy  func() interface{}
+}
+
+// String returns a field containing a string
+func String(key, value string) Field {
+	return Field{Key: key, value: value}
+}
+
+// Int returns a field containing an int
+func Int(key string, value int) Field {
+	return Field{Key: key, value: value}
+}
+
+// Duration returns a field containing a duration
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, value: value}
+}
+
+// ErrorField returns a field containing an error, using ErrorKey as key.
+func ErrorField(err error) Field {
+	return Field{Key: ErrorKey, value: err}
+}
+
+// Any returns a field containing any value
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, value: value}
+}
+
+// Lazy returns a field which value is computed by fn.
+// fn is only called if the entry is going to be logged, which makes Lazy
+// useful for values that are expensive to compute
+func Lazy(key string, fn func() interface{}) Field {
+	return Field{Key: key, lazy: fn}
+}
+
+// Value returns the value of the field, calling the function of a lazy
+// field
+func (f Field) Value() interface{} {
+	if f.lazy != nil {
+		return f.lazy()
+	}
+	return f.value
+}
+
+// splitFields separates the fields from the other arguments
+func splitFields(args []interface{}) ([]interface{}, []Field) {
+	// Most calls don't contain any fields, in which case we don't want
+	// to allocate anything
+	n := 0
+	for _, arg := range args {
+		if _, ok := arg.(Field); ok {
+			n++
+		}
+	}
+	if n == 0 {
+		return args, nil
+	}
+
+	rest := make([]interface{}, 0, len(args)-n)
+	fields := make([]Field, 0, n)
+	for _, arg := range args {
+		if f, ok := arg.(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, fields
+}
+
+// fieldValues evaluates the given fields
+func fieldValues(fields []Field) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	values := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		values[f.Key] = f.Value()
+	}
+	return values
+}
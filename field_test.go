@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nopEntryLogger is a SliceLogger that drops the structured entries
+type nopEntryLogger struct {
+	SliceLogger
+}
+
+func (l *nopEntryLogger) LogEntry(e *Entry) error {
+	return nil
+}
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("not found")
+	testCases := []struct {
+		description string
+		field       Field
+		key         string
+		value       interface{}
+	}{
+		{"String", String("user", "john"), "user", "john"},
+		{"Int", Int("count", 2), "count", 2},
+		{"Duration", Duration("elapsed", time.Second), "elapsed", time.Second},
+		{"ErrorField", ErrorField(err), ErrorKey, err},
+		{"Any", Any("ids", []int{1, 2}), "ids", []int{1, 2}},
+		{"Lazy", Lazy("lazy", func() interface{} { return "value" }), "lazy", "value"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.key, tc.field.Key)
+			assert.Equal(t, tc.value, tc.field.Value())
+		})
+	}
+}
+
+func TestManagerFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fields are added to the entry", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		l := &entrySliceLogger{}
+		require.NoError(t, m.Add(l))
+		m.AddGlobalData("user", "john")
+		m.AddGlobalData("count", 1)
+
+		m.Error("could not save", Int("count", 2), String("id", "abc"))
+		m.Infof("%d users", String("id", "def"), 3)
+
+		require.Len(t, l.entries, 2, "no entries added")
+		assert.Equal(t, "could not save", l.entries[0].Message)
+		assert.Equal(t, map[string]interface{}{"count": 2, "id": "abc"}, l.entries[0].Fields)
+		assert.Equal(t, map[string]interface{}{"user": "john", "count": 2, "id": "abc"}, l.entries[0].Data(), "the fields should take precedence over the globals")
+		assert.Equal(t, "3 users", l.entries[1].Message)
+		assert.Equal(t, map[string]interface{}{"id": "def"}, l.entries[1].Fields)
+	})
+
+	t.Run("fields are printed with the globals", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		l := &SliceLogger{}
+		require.NoError(t, m.Add(l))
+		m.AddGlobalData("user", "john")
+
+		m.Info("message", String("id", "abc"))
+		require.Len(t, l.data, 1, "no entries added")
+		assert.Equal(t, "[INFO]message\n{\"id\":\"abc\",\"user\":\"john\"}\n", l.data[0])
+	})
+
+	t.Run("lazy fields are not evaluated when the entry is dropped", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		l := &entrySliceLogger{}
+		require.NoError(t, m.Add(l))
+		m.SetMinLevel(LevelInfo)
+
+		calls := 0
+		field := Lazy("dump", func() interface{} {
+			calls++
+			return "value"
+		})
+
+		m.Debug("dropped", field)
+		assert.Equal(t, 0, calls, "the field should not have been evaluated")
+
+		m.Info("logged", field)
+		assert.Equal(t, 1, calls, "the field should have been evaluated once")
+		require.Len(t, l.entries, 1, "no entries added")
+		assert.Equal(t, "value", l.entries[0].Fields["dump"])
+	})
+
+	t.Run("entries are not formatted for entry loggers", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		l := &entrySliceLogger{}
+		require.NoError(t, m.Add(l))
+		// json.Marshal fails on channels
+		m.AddGlobalData("chan", make(chan int))
+
+		assert.NotPanics(t, func() {
+			m.Info("message")
+		})
+		require.Len(t, l.entries, 1, "no entries added")
+	})
+}
+
+func BenchmarkManager(b *testing.B) {
+	newManager := func(l Logger) Manager {
+		m := NewManager()
+		if err := m.Add(l); err != nil {
+			b.Fatal(err)
+		}
+		m.AddGlobalData("user", "john")
+		m.AddGlobalData("request_id", "4f2b6b7e")
+		return m
+	}
+
+	b.Run("format path", func(b *testing.B) {
+		l := &SliceLogger{}
+		m := newManager(l)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			m.Info("could not save", 42)
+			// we don't want to measure the growth of the slice
+			if i%1000 == 0 {
+				l.data = nil
+			}
+		}
+	})
+
+	b.Run("entry path with fields", func(b *testing.B) {
+		m := newManager(&nopEntryLogger{})
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			m.Info("could not save", Int("count", 42))
+		}
+	})
+
+	b.Run("dropped entry", func(b *testing.B) {
+		m := newManager(&nopEntryLogger{})
+		m.SetMinLevel(LevelInfo)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			m.Debug("could not save", Lazy("count", func() interface{} { return 42 }))
+		}
+	})
+}
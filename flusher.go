@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// SignalFlushTimeout is the maximum amount of time HandleSignals waits for
+// the loggers to be flushed
+const SignalFlushTimeout = 5 * time.Second
+
+// Flusher is an optional interface that can be implemented by a Logger
+// that buffers its entries
+type Flusher interface {
+	// Flush writes all the buffered entries
+	Flush() error
+}
+
+// SignalOption is an option of HandleSignals
+type SignalOption func(*signalConfig)
+
+// signalConfig contains the configuration of HandleSignals
+type signalConfig struct {
+	signals []os.Signal
+	reraise bool
+}
+
+// Signals sets the signals handled by HandleSignals. Defaults to SIGINT
+// and SIGTERM
+func Signals(sigs ...os.Signal) SignalOption {
+	return func(cfg *signalConfig) {
+		cfg.signals = sigs
+	}
+}
+
+// NoReraise prevents HandleSignals from sending the signal again once the
+// loggers have been flushed. It should be used by applications that
+// handle the signals themselves, since their own signal.Notify channels
+// would otherwise receive the signal twice
+func NoReraise() SignalOption {
+	return func(cfg *signalConfig) {
+		cfg.reraise = false
+	}
+}
+
+// HandleSignals flushes the loggers of the default manager when the
+// process receives one of the handled signals, then sends the signal
+// again so the process handles it as it normally would. The signals are
+// only handled once, so the next ones have their usual effect.
+// Returns a function that stops handling the signals
+func HandleSignals(opts ...SignalOption) (stop func()) {
+	cfg := &signalConfig{
+		signals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+		reraise: true,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, cfg.signals...)
+
+	done := make(chan struct{})
+	go watchSignals(Default(), ch, done, func(sig os.Signal) {
+		signal.Stop(ch)
+		if !cfg.reraise {
+			return
+		}
+		if p, err := os.FindProcess(os.Getpid()); err == nil {
+			p.Signal(sig) //nolint:errcheck
+		}
+	})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// watchSignals flushes m when a signal is received on ch, then calls
+// handled with the signal
+func watchSignals(m Manager, ch <-chan os.Signal, done <-chan struct{}, handled func(os.Signal)) {
+	select {
+	case sig := <-ch:
+		ctx, cancel := context.WithTimeout(context.Background(), SignalFlushTimeout)
+		defer cancel()
+		m.Flush(ctx) //nolint:errcheck
+		handled(sig)
+	case <-done:
+	}
+}
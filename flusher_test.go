@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flushSliceLogger is a SliceLogger that implements Flusher
+type flushSliceLogger struct {
+	SliceLogger
+	flushed  chan struct{}
+	block    chan struct{}
+	flushErr error
+}
+
+func newFlushSliceLogger(id string) *flushSliceLogger {
+	return &flushSliceLogger{
+		SliceLogger: SliceLogger{id: id},
+		flushed:     make(chan struct{}, 10),
+	}
+}
+
+func (l *flushSliceLogger) Flush() error {
+	if l.block != nil {
+		<-l.block
+	}
+	l.flushed <- struct{}{}
+	return l.flushErr
+}
+
+func (l *flushSliceLogger) Close() error {
+	if l.block != nil {
+		<-l.block
+	}
+	return nil
+}
+
+func TestManagerFlush(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loggers of the tree are flushed once", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		sm := m.NewSubManager("[child]")
+
+		l1 := newFlushSliceLogger("l1")
+		l2 := newFlushSliceLogger("l2")
+		require.NoError(t, m.Add(l1))
+		require.NoError(t, m.Add(NewSliceLogger()))
+		require.NoError(t, sm.Add(l1))
+		require.NoError(t, sm.Add(l2))
+
+		assert.Empty(t, m.Flush(context.Background()))
+		assert.Len(t, l1.flushed, 1)
+		assert.Len(t, l2.flushed, 1)
+	})
+
+	t.Run("errors are returned", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		l := newFlushSliceLogger("l")
+		l.flushErr = errors.New("disk full")
+		require.NoError(t, m.Add(l))
+
+		errs := m.Flush(context.Background())
+		require.Len(t, errs, 1)
+		require.IsType(t, &Err{}, errs[0])
+		assert.Equal(t, l, errs[0].(*Err).Logger)
+		assert.Equal(t, l.flushErr, errs[0].(*Err).error)
+	})
+
+	t.Run("stuck loggers are reported", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		l := newFlushSliceLogger("l")
+		l.block = make(chan struct{})
+		defer close(l.block)
+		require.NoError(t, m.Add(l))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		errs := m.Flush(ctx)
+		require.Len(t, errs, 1)
+		assert.Equal(t, context.DeadlineExceeded, errors.Cause(errs[0].(*Err).error))
+	})
+}
+
+func TestManagerCloseContext(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager()
+	sm := m.NewSubManager("[child]")
+	stuck := newFlushSliceLogger("stuck")
+	stuck.block = make(chan struct{})
+	defer close(stuck.block)
+	require.NoError(t, m.Add(NewSliceLogger()))
+	require.NoError(t, sm.Add(stuck))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	errs := m.CloseContext(ctx)
+	require.Len(t, errs, 1)
+	assert.Equal(t, stuck, errs[0].(*Err).Logger)
+	assert.Empty(t, m.Loggers())
+	assert.Empty(t, m.Children())
+}
+
+func TestWatchSignals(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loggers are flushed before raising the signal", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		l := newFlushSliceLogger("l")
+		require.NoError(t, m.Add(l))
+
+		ch := make(chan os.Signal, 1)
+		raised := make(chan os.Signal, 1)
+		ch <- os.Interrupt
+		watchSignals(m, ch, make(chan struct{}), func(sig os.Signal) {
+			assert.Len(t, l.flushed, 1, "the logger should have been flushed")
+			raised <- sig
+		})
+		assert.Equal(t, os.Interrupt, <-raised)
+	})
+
+	t.Run("nothing happens once stopped", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		close(done)
+		watchSignals(NewManager(), make(chan os.Signal), done, func(os.Signal) {
+			assert.Fail(t, "no signals should have been raised")
+		})
+	})
+}
+
+func TestHandleSignals(t *testing.T) {
+	t.Parallel()
+
+	stop := HandleSignals()
+	stop()
+	stop()
+}
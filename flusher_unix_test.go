@@ -0,0 +1,74 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This test sends signals to the process and uses the default manager,
+// so it cannot run in parallel
+func TestHandleSignalsWithNotify(t *testing.T) {
+	SetDefault(nil)
+	l := newFlushSliceLogger("signals")
+	require.NoError(t, Add(l))
+	defer Remove(l.ID()) //nolint:errcheck
+
+	// the application has its own handler
+	appCh := make(chan os.Signal, 10)
+	signal.Notify(appCh, syscall.SIGUSR1)
+	defer signal.Stop(appCh)
+
+	// received returns the number of signals received by the
+	// application within a short period
+	received := func() int {
+		count := 0
+		for {
+			select {
+			case <-appCh:
+				count++
+			case <-time.After(200 * time.Millisecond):
+				return count
+			}
+		}
+	}
+	raise := func() {
+		p, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		require.NoError(t, p.Signal(syscall.SIGUSR1))
+	}
+
+	t.Run("NoReraise doesn't send the signal again", func(t *testing.T) {
+		stop := HandleSignals(Signals(syscall.SIGUSR1), NoReraise())
+		defer stop()
+
+		raise()
+		select {
+		case <-l.flushed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the loggers have not been flushed")
+		}
+		assert.Equal(t, 1, received(), "the application should receive the signal once")
+	})
+
+	t.Run("the signal is sent again by default", func(t *testing.T) {
+		stop := HandleSignals(Signals(syscall.SIGUSR1))
+		defer stop()
+
+		raise()
+		select {
+		case <-l.flushed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the loggers have not been flushed")
+		}
+		assert.Equal(t, 2, received(), "the application should receive the signal a second time")
+	})
+}
@@ -0,0 +1,8 @@
+package logger
+
+// Formatter is an interface used to encode entries
+type Formatter interface {
+	// Format returns the encoded version of an entry, including its
+	// trailing new line
+	Format(e *Entry) ([]byte, error)
+}
@@ -1,6 +1,6 @@
 module github.com/Nivl/go-logger
 
-go 1.12
+go 1.14
 
 require (
 	github.com/go-critic/go-critic v0.0.0-20181204210945-ee9bf5809ead // indirect
@@ -11,7 +11,7 @@ require (
 	github.com/stretchr/testify v1.2.2
 	golang.org/x/crypto v0.0.0-20190426145343-a29dc8fdc734 // indirect
 	golang.org/x/net v0.0.0-20190503192946-f4e77d36d62c // indirect
-	golang.org/x/sys v0.0.0-20190502175342-a43fa875dd82 // indirect
+	golang.org/x/sys v0.0.0-20190502175342-a43fa875dd82
 	golang.org/x/text v0.3.2 // indirect
 	golang.org/x/tools v0.0.0-20190503185657-3b6f9c0030f7 // indirect
 )
@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// DefaultRequestIDHeader is the header used by HTTPMiddleware to read
+// and write the request IDs
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// we make sure HTTPMiddleware implements http.Handler
+var _ http.Handler = (*HTTPMiddleware)(nil)
+
+// NewHTTPMiddleware creates and returns an http.Handler that logs the
+// requests handled by next
+func NewHTTPMiddleware(m Manager, next http.Handler) *HTTPMiddleware {
+	return &HTTPMiddleware{
+		RequestIDHeader: DefaultRequestIDHeader,
+		manager:         m,
+		next:            next,
+		now:             time.Now,
+	}
+}
+
+// HTTPMiddleware is an http.Handler that creates a submanager for each
+// request, and logs an entry once the request is done.
+//
+// The submanager contains the ID, method, path and remote address of the
+// request as globals, and is stored in the context of the request. It
+// can be retrieved using FromContext. Once the request is done, the
+// submanager is removed from the children of the manager, but is not
+// closed: goroutines started by the request can keep logging with it.
+// The loggers added to the submanager are not closed either.
+//
+// The entry logged at the end of the request contains the status code,
+// the number of bytes written and the latency. It's logged as an error
+// for 5xx, with Log for 4xx, and as an info otherwise.
+//
+// Panics are recovered and logged with their stack trace
+type HTTPMiddleware struct {
+	// RequestIDHeader is the header containing the ID of the request. A
+	// new ID is generated for the requests that don't have one.
+	// The ID is also sent back in the response.
+	// Defaults to DefaultRequestIDHeader
+	RequestIDHeader string
+
+	manager Manager
+	next    http.Handler
+	now     func() time.Time
+}
+
+// ServeHTTP implements http.Handler
+func (h *HTTPMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := h.now()
+
+	id := r.Header.Get(h.RequestIDHeader)
+	if id == "" {
+		id = uuid.New().String()
+	}
+	w.Header().Set(h.RequestIDHeader, id)
+
+	m := h.manager.NewSubManager("")
+	defer release(m)
+	m.AddGlobalData("request_id", id)
+	m.AddGlobalData("method", r.Method)
+	m.AddGlobalData("path", r.URL.Path)
+	m.AddGlobalData("remote_addr", r.RemoteAddr)
+
+	ctx := NewContext(r.Context(), m)
+	m.SetContext(ctx)
+	r = r.WithContext(ctx)
+
+	sw := &statusWriter{ResponseWriter: w}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// http.ErrAbortHandler is used to abort a response, and should
+			// not be logged
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			logPanic(m, rec, debug.Stack())
+			if !sw.wroteHeader {
+				http.Error(sw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}
+
+		status := sw.status
+		if !sw.wroteHeader {
+			status = http.StatusOK
+		}
+		fields := []interface{}{
+			fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, status),
+			Int("status", status),
+			Int("bytes", sw.bytes),
+			Duration("latency", h.now().Sub(start)),
+		}
+		switch {
+		case status >= 500:
+			m.Error(fields...)
+		case status >= 400:
+			m.Log(fields...)
+		default:
+			m.Info(fields...)
+		}
+	}()
+
+	h.next.ServeHTTP(sw, r)
+}
+
+// release removes the submanager of a request from the tree of managers.
+// Managers that can't be detached from their parent are closed
+func release(m Manager) {
+	if d, ok := m.(interface{ detachFromParent() }); ok {
+		d.detachFromParent()
+		return
+	}
+	m.Close() //nolint:errcheck
+}
+
+// statusWriter is an http.ResponseWriter that records the status and
+// the number of bytes of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.bytes += n
+	return n, err
+}
+
+// Flush implements http.Flusher
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the response writer cannot be hijacked")
+	}
+	return h.Hijack()
+}
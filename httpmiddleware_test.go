@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	// newMiddleware returns a middleware which requests last 1.5s
+	newMiddleware := func(next http.HandlerFunc) (*HTTPMiddleware, Manager, *entrySliceLogger) {
+		m := NewManagerWithTag("[http]")
+		l := &entrySliceLogger{}
+		require.NoError(t, m.Add(l))
+
+		h := NewHTTPMiddleware(m, next)
+		start := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		calls := 0
+		h.now = func() time.Time {
+			calls++
+			if calls == 1 {
+				return start
+			}
+			return start.Add(1500 * time.Millisecond)
+		}
+		return h, m, l
+	}
+
+	t.Run("completed requests are logged", func(t *testing.T) {
+		t.Parallel()
+
+		var fromCtx Manager
+		h, m, l := newMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			fromCtx = FromContext(r.Context())
+			fromCtx.Debug("handling")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("hello")) //nolint:errcheck
+		})
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/users?x=1", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Request-ID", "abc")
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "abc", rec.Header().Get("X-Request-ID"))
+		require.NotNil(t, fromCtx)
+		assert.False(t, fromCtx.IsClosed(), "the request manager should not be closed")
+		assert.Empty(t, m.Children(), "the request manager should be detached")
+
+		require.Len(t, l.entries, 2)
+		globals := map[string]interface{}{
+			"request_id":  "abc",
+			"method":      http.MethodPost,
+			"path":        "/users",
+			"remote_addr": "10.0.0.1:1234",
+		}
+		assert.Equal(t, "handling", l.entries[0].Message)
+		assert.Equal(t, globals, l.entries[0].Globals)
+		assert.Equal(t, fromCtx, FromContext(l.entries[0].Context), "the context of the entries should contain the manager")
+
+		e := l.entries[1]
+		assert.Equal(t, LevelInfo, e.Level)
+		assert.Equal(t, "POST /users 201", e.Message)
+		assert.Equal(t, "[http]", e.Tag)
+		assert.Equal(t, globals, e.Globals)
+		assert.Equal(t, map[string]interface{}{
+			"status":  http.StatusCreated,
+			"bytes":   5,
+			"latency": 1500 * time.Millisecond,
+		}, e.Fields)
+	})
+
+	t.Run("the request manager can be used after the request", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		done := make(chan struct{})
+		h, m, l := newMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			job := FromContext(r.Context()).NewSubManager("[job]")
+			go func() {
+				defer close(done)
+				<-release
+				FromContext(r.Context()).Info("background job done")
+				FromContext(r.Context()).Debug("dropped")
+				job.Info("job done")
+			}()
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-ID", "abc")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		// the settings changed after the request should be inherited
+		m.AddGlobalData("env", "prod")
+		m.SetMinLevel(LevelInfo)
+		close(release)
+		<-done
+
+		require.Len(t, l.entries, 3)
+		assert.Equal(t, "background job done", l.entries[1].Message)
+		assert.Equal(t, "abc", l.entries[1].Globals["request_id"])
+		assert.Equal(t, "prod", l.entries[1].Globals["env"])
+		assert.Equal(t, "job done", l.entries[2].Message)
+		assert.Equal(t, "prod", l.entries[2].Globals["env"])
+	})
+
+	t.Run("request IDs are generated", func(t *testing.T) {
+		t.Parallel()
+
+		h, _, l := newMiddleware(func(w http.ResponseWriter, r *http.Request) {})
+		h.RequestIDHeader = "X-Trace"
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		id := rec.Header().Get("X-Trace")
+		assert.Len(t, id, 36, "a UUID should have been generated")
+		require.Len(t, l.entries, 1)
+		assert.Equal(t, id, l.entries[0].Globals["request_id"])
+		assert.Equal(t, http.StatusOK, l.entries[0].Fields["status"], "the default status should be used")
+	})
+
+	t.Run("the level depends on the status", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			status int
+			level  Level
+		}{
+			{http.StatusOK, LevelInfo},
+			{http.StatusFound, LevelInfo},
+			{http.StatusNotFound, LevelDefault},
+			{http.StatusServiceUnavailable, LevelError},
+		}
+		for _, tc := range testCases {
+			status := tc.status
+			h, _, l := newMiddleware(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+			})
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			require.Len(t, l.entries, 1)
+			assert.Equal(t, tc.level, l.entries[0].Level, http.StatusText(tc.status))
+		}
+	})
+
+	t.Run("panics are recovered", func(t *testing.T) {
+		t.Parallel()
+
+		h, _, l := newMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+
+		rec := httptest.NewRecorder()
+		assert.NotPanics(t, func() {
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		require.Len(t, l.entries, 2)
+		assert.Equal(t, LevelError, l.entries[0].Level)
+		assert.Equal(t, "panic: boom", l.entries[0].Message)
+		assert.Contains(t, l.entries[0].Fields["stack"], "httpmiddleware_test.go")
+		assert.Equal(t, "GET / 500", l.entries[1].Message)
+		assert.Equal(t, LevelError, l.entries[1].Level)
+	})
+
+	t.Run("ErrAbortHandler is not recovered", func(t *testing.T) {
+		t.Parallel()
+
+		h, _, _ := newMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})
+		assert.Panics(t, func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	})
+
+	t.Run("the response writer can be flushed", func(t *testing.T) {
+		t.Parallel()
+
+		h, _, _ := newMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			f, ok := w.(http.Flusher)
+			require.True(t, ok)
+			f.Flush()
+		})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.True(t, rec.Flushed)
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager()
+	ctx := NewContext(context.Background(), m)
+	assert.Equal(t, m, FromContext(ctx))
+	assert.Equal(t, Default(), FromContext(context.Background()), "the default manager should be returned")
+}
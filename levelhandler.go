@@ -0,0 +1,225 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// we make sure LevelHandler implements http.Handler
+var _ http.Handler = (*LevelHandler)(nil)
+
+// NewLevelHandler creates and returns an http.Handler that exposes the
+// tree of m, and allows changing the minimum level of its managers
+// at runtime
+func NewLevelHandler(m Manager) *LevelHandler {
+	return &LevelHandler{
+		root:    m,
+		reverts: map[string]*pendingRevert{},
+	}
+}
+
+// LevelHandler is an http.Handler used to change the minimum level of
+// the managers of a tree at runtime.
+//
+// GET returns the tree of managers as JSON.
+//
+// PUT changes the minimum level of the managers matching either an ID
+// or a full tag, and returns the updated managers. An empty level
+// removes the minimum level of the managers. If a TTL is provided, the
+// previous levels are restored once it expires:
+//
+//	{"id": "...", "level": "debug", "ttl": "10m"}
+//	{"tag": "[app][db]", "level": "debug"}
+type LevelHandler struct {
+	root Manager
+
+	mu      sync.Mutex
+	reverts map[string]*pendingRevert
+}
+
+// pendingRevert contains the level to restore once a TTL expires
+type pendingRevert struct {
+	timer    *time.Timer
+	level    Level
+	hasLevel bool
+}
+
+// managerNode is the JSON representation of a manager
+type managerNode struct {
+	ID       string         `json:"id"`
+	Tag      string         `json:"tag"`
+	FullTag  string         `json:"full_tag"`
+	Level    *Level         `json:"level"`
+	MinLevel Level          `json:"min_level"`
+	Loggers  []string       `json:"loggers"`
+	Children []*managerNode `json:"children,omitempty"`
+}
+
+// levelRequest is the payload of a PUT request
+type levelRequest struct {
+	ID    string `json:"id"`
+	Tag   string `json:"tag"`
+	Level string `json:"level"`
+	TTL   string `json:"ttl"`
+}
+
+// ServeHTTP implements http.Handler
+func (h *LevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, newManagerNode(h.root, true))
+	case http.MethodPut:
+		h.update(w, r)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *LevelHandler) update(w http.ResponseWriter, r *http.Request) {
+	req := &levelRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, "invalid JSON payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if (req.ID == "") == (req.Tag == "") {
+		http.Error(w, "either id or tag must be provided", http.StatusBadRequest)
+		return
+	}
+
+	var lvl Level
+	if req.Level != "" {
+		var err error
+		if lvl, err = ParseLevel(req.Level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+			http.Error(w, "invalid ttl "+req.TTL, http.StatusBadRequest)
+			return
+		}
+	}
+
+	var managers []Manager
+	h.root.Walk(func(m Manager) bool {
+		if (req.ID != "" && m.ID() == req.ID) || (req.Tag != "" && m.FullTag() == req.Tag) {
+			managers = append(managers, m)
+		}
+		return true
+	})
+	if len(managers) == 0 {
+		http.Error(w, "no managers found", http.StatusNotFound)
+		return
+	}
+
+	nodes := make([]*managerNode, 0, len(managers))
+	for _, m := range managers {
+		h.setLevel(m, req.Level != "", lvl, ttl)
+		nodes = append(nodes, newManagerNode(m, false))
+	}
+	writeJSON(w, http.StatusOK, nodes)
+}
+
+// setLevel sets or clears the level of a manager, and schedules a revert
+// if ttl is not 0
+func (h *LevelHandler) setLevel(m Manager, hasLevel bool, lvl Level, ttl time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// If a change is already pending, we keep the level it would have
+	// restored since it's the original one. A new revert is created so
+	// the timer of the previous one does nothing if it already fired
+	revert := &pendingRevert{}
+	if pending, ok := h.reverts[m.ID()]; ok {
+		pending.timer.Stop()
+		delete(h.reverts, m.ID())
+		revert.level, revert.hasLevel = pending.level, pending.hasLevel
+	} else {
+		revert.level, revert.hasLevel = explicitMinLevel(m)
+	}
+
+	applyLevel(m, hasLevel, lvl)
+	if ttl == 0 {
+		return
+	}
+
+	h.reverts[m.ID()] = revert
+	revert.timer = time.AfterFunc(ttl, func() {
+		h.revert(m, revert)
+	})
+}
+
+// revert restores the level of a manager once the TTL of a change
+// expired
+func (h *LevelHandler) revert(m Manager, revert *pendingRevert) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The revert may have been replaced while we were waiting for
+	// the lock
+	if h.reverts[m.ID()] != revert {
+		return
+	}
+	delete(h.reverts, m.ID())
+	applyLevel(m, revert.hasLevel, revert.level)
+}
+
+func applyLevel(m Manager, hasLevel bool, lvl Level) {
+	if hasLevel {
+		m.SetMinLevel(lvl)
+		return
+	}
+	m.ClearMinLevel()
+}
+
+// explicitMinLevel returns the minimum level set on the manager itself,
+// and false if the manager uses the level of its parent
+func explicitMinLevel(m Manager) (Level, bool) {
+	dm, ok := m.(*DefaultManager)
+	if !ok {
+		// We can't tell if the level is inherited, so we assume it's not
+		return m.MinLevel(), true
+	}
+
+	dm.RLock()
+	defer dm.RUnlock()
+	return dm.minLevel, dm.hasMinLevel
+}
+
+// newManagerNode returns the JSON representation of a manager
+func newManagerNode(m Manager, withChildren bool) *managerNode {
+	node := &managerNode{
+		ID:       m.ID(),
+		Tag:      m.Tag(),
+		FullTag:  m.FullTag(),
+		MinLevel: m.MinLevel(),
+		Loggers:  []string{},
+	}
+	if lvl, ok := explicitMinLevel(m); ok {
+		node.Level = &lvl
+	}
+	for _, l := range m.Loggers() {
+		node.Loggers = append(node.Loggers, l.ID())
+	}
+
+	if withChildren {
+		for _, c := range m.Children() {
+			node.Children = append(node.Children, newManagerNode(c, true))
+		}
+	}
+	return node
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// Nothing can be done if the response can't be written
+	_ = json.NewEncoder(w).Encode(data) //nolint:errcheck
+}
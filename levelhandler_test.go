@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelHandler(t *testing.T) {
+	t.Parallel()
+
+	newTree := func() (m, db Manager) {
+		m = NewManagerWithTag("[app]")
+		require.NoError(t, m.Add(NewSliceLogger()))
+		m.SetMinLevel(LevelInfo)
+		db = m.NewSubManager("[db]")
+		m.NewSubManager("[http]")
+		return m, db
+	}
+
+	request := func(h http.Handler, method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("GET returns the tree", func(t *testing.T) {
+		t.Parallel()
+		m, db := newTree()
+		h := NewLevelHandler(m)
+
+		rec := request(h, http.MethodGet, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		root := &managerNode{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), root))
+		assert.Equal(t, m.ID(), root.ID)
+		assert.Equal(t, "[app]", root.Tag)
+		require.NotNil(t, root.Level)
+		assert.Equal(t, LevelInfo, *root.Level)
+		assert.Equal(t, []string{"slice-logger"}, root.Loggers)
+
+		require.Len(t, root.Children, 2)
+		assert.Equal(t, db.ID(), root.Children[0].ID)
+		assert.Equal(t, "[app][db]", root.Children[0].FullTag)
+		assert.Nil(t, root.Children[0].Level)
+		assert.Equal(t, LevelInfo, root.Children[0].MinLevel)
+		assert.Equal(t, "[http]", root.Children[1].Tag)
+	})
+
+	t.Run("PUT by ID", func(t *testing.T) {
+		t.Parallel()
+		m, db := newTree()
+		h := NewLevelHandler(m)
+
+		rec := request(h, http.MethodPut, `{"id":"`+db.ID()+`","level":"debug"}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, LevelDebug, db.MinLevel())
+		assert.Equal(t, LevelInfo, m.MinLevel())
+
+		nodes := []*managerNode{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &nodes))
+		require.Len(t, nodes, 1)
+		assert.Equal(t, db.ID(), nodes[0].ID)
+	})
+
+	t.Run("PUT by tag", func(t *testing.T) {
+		t.Parallel()
+		m, db := newTree()
+		h := NewLevelHandler(m)
+		db.SetMinLevel(LevelError)
+
+		rec := request(h, http.MethodPut, `{"tag":"[app][db]"}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, LevelInfo, db.MinLevel(), "the level should have been cleared")
+	})
+
+	t.Run("PUT with a TTL", func(t *testing.T) {
+		t.Parallel()
+		m, db := newTree()
+		h := NewLevelHandler(m)
+
+		rec := request(h, http.MethodPut, `{"tag":"[app][db]","level":"debug","ttl":"1h"}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, LevelDebug, db.MinLevel())
+
+		// A second change should keep the original level to restore
+		rec = request(h, http.MethodPut, `{"tag":"[app][db]","level":"error","ttl":"10ms"}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, LevelError, db.MinLevel())
+
+		deadline := time.Now().Add(time.Second)
+		for db.MinLevel() == LevelError && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		assert.Equal(t, LevelInfo, db.MinLevel(), "the level should have been reverted")
+		db.(*DefaultManager).RLock()
+		assert.False(t, db.(*DefaultManager).hasMinLevel, "the level should have been cleared")
+		db.(*DefaultManager).RUnlock()
+	})
+
+	t.Run("an expired TTL doesn't revert the change replacing it", func(t *testing.T) {
+		t.Parallel()
+		m, db := newTree()
+		h := NewLevelHandler(m)
+
+		h.setLevel(db, true, LevelDebug, time.Hour)
+		h.mu.Lock()
+		expired := h.reverts[db.ID()]
+		h.mu.Unlock()
+
+		h.setLevel(db, true, LevelError, time.Hour)
+		// the timer of the first change fired before being stopped
+		h.revert(db, expired)
+		assert.Equal(t, LevelError, db.MinLevel(), "the level should not have been reverted")
+
+		h.mu.Lock()
+		pending := h.reverts[db.ID()]
+		h.mu.Unlock()
+		require.NotNil(t, pending, "the revert of the second change should still be pending")
+		pending.timer.Stop()
+		h.revert(db, pending)
+		assert.Equal(t, LevelInfo, db.MinLevel(), "the original level should be restored")
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		t.Parallel()
+		m, _ := newTree()
+		h := NewLevelHandler(m)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, request(h, http.MethodPost, "").Code)
+		assert.Equal(t, http.StatusBadRequest, request(h, http.MethodPut, `nope`).Code)
+		assert.Equal(t, http.StatusBadRequest, request(h, http.MethodPut, `{"level":"debug"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request(h, http.MethodPut, `{"id":"a","tag":"b"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request(h, http.MethodPut, `{"id":"a","level":"nope"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, request(h, http.MethodPut, `{"id":"a","ttl":"nope"}`).Code)
+		assert.Equal(t, http.StatusNotFound, request(h, http.MethodPut, `{"id":"a"}`).Code)
+	})
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"sync"
+)
+
+// fallback contains the logger used by the closed managers
+var fallback = struct {
+	sync.RWMutex
+	l Logger
+}{
+	l: NewStderrLogger(),
+}
+
+// SetFallbackLogger sets the logger that receives the entries logged
+// by closed managers, which have no loggers anymore.
+// Defaults to a StderrLogger. A nil logger drops the entries
+func SetFallbackLogger(l Logger) {
+	fallback.Lock()
+	defer fallback.Unlock()
+	fallback.l = l
+}
+
+// FallbackLogger returns the logger that receives the entries logged
+// by closed managers
+func FallbackLogger() Logger {
+	fallback.RLock()
+	defer fallback.RUnlock()
+	return fallback.l
+}
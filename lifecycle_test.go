@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This test changes the fallback logger, so it cannot run in parallel
+func TestManagerClosed(t *testing.T) {
+	fl := &entrySliceLogger{}
+	defer SetFallbackLogger(FallbackLogger())
+	SetFallbackLogger(fl)
+
+	m := NewManagerWithTag("[app]")
+	sm := m.NewSubManager("[sub]")
+	l := &entrySliceLogger{}
+	require.NoError(t, m.Add(l))
+	assert.False(t, m.IsClosed())
+	assert.False(t, sm.IsClosed())
+
+	require.Empty(t, m.Close())
+	assert.True(t, m.IsClosed())
+	assert.True(t, sm.IsClosed(), "the submanagers should be closed")
+
+	t.Run("Add returns ErrClosed", func(t *testing.T) {
+		assert.Equal(t, ErrClosed, m.Add(&entrySliceLogger{}))
+		assert.Equal(t, ErrClosed, sm.Add(&entrySliceLogger{}))
+		assert.Empty(t, m.Loggers())
+	})
+
+	t.Run("submanagers of a closed manager are closed", func(t *testing.T) {
+		ssm := sm.NewSubManager("[child]")
+		assert.True(t, ssm.IsClosed())
+		assert.Empty(t, sm.Children(), "the submanager should not be attached")
+		assert.Equal(t, "[app][sub][child]", ssm.FullTag())
+	})
+
+	t.Run("entries are sent to the fallback logger", func(t *testing.T) {
+		sm.Error("after close", String("key", "value"))
+		assert.Empty(t, l.entries, "the logger should have been removed")
+		require.Len(t, fl.entries, 1, "the fallback logger should have been used")
+		assert.Equal(t, "after close", fl.entries[0].Message)
+		assert.Equal(t, "[app][sub]", fl.entries[0].Tag)
+	})
+
+	t.Run("entries are dropped without fallback logger", func(t *testing.T) {
+		SetFallbackLogger(nil)
+		assert.NotPanics(t, func() {
+			m.Info("dropped")
+		})
+	})
+}
+
+func TestManagerDetachedClosedParent(t *testing.T) {
+	fl := &entrySliceLogger{}
+	defer SetFallbackLogger(FallbackLogger())
+	SetFallbackLogger(fl)
+
+	m := NewManagerWithTag("[app]")
+	l := &entrySliceLogger{}
+	require.NoError(t, m.Add(l))
+	sm := m.NewSubManager("[sub]")
+	ssm := sm.NewSubManager("[child]")
+	sm.(*DefaultManager).detachFromParent()
+
+	require.Empty(t, m.Close())
+	assert.False(t, sm.IsClosed(), "a detached manager should not be closed with its parent")
+
+	sm.Info("after close")
+	ssm.Info("after close")
+	assert.Empty(t, l.entries, "the logger should have been removed")
+	require.Len(t, fl.entries, 2, "the fallback logger should have been used")
+	assert.Equal(t, "[app][sub]", fl.entries[0].Tag)
+	assert.Equal(t, "[app][sub][child]", fl.entries[1].Tag)
+}
+
+func TestManagerClosedLoggers(t *testing.T) {
+	t.Parallel()
+
+	m1 := NewManager()
+	m2 := NewManager()
+	l := &SliceLogger{}
+	require.NoError(t, m1.Add(l))
+	require.NoError(t, m2.Add(l))
+
+	// the logger is closed by someone else
+	require.NoError(t, l.Close())
+	require.Len(t, m2.Loggers(), 1)
+
+	m2.Info("message")
+	assert.Empty(t, l.data, "a closed logger should not be used")
+	assert.Empty(t, m2.Loggers(), "the closed logger should have been detached")
+	assert.Len(t, m1.Loggers(), 1, "the logger has not been used by m1 yet")
+
+	// A new logger using the same ID can be added
+	require.NoError(t, m2.Add(&SliceLogger{}))
+	m2.Info("message")
+	assert.Len(t, m2.Loggers(), 1)
+}
+
+func TestManagerCloseWhileParentChanges(t *testing.T) {
+	t.Parallel()
+
+	// A closing child locks itself then its parent, so changing the
+	// parent must not lock its children while holding its own lock
+	m := NewManager()
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+					m.AddGlobalData("k", i)
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 2000; i++ {
+			m.NewSubManager("[child]").Close()
+		}
+		close(stop)
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the managers are deadlocked")
+	}
+}
+
+// This test changes the output of the log package, so it cannot run in
+// parallel
+func TestManagerSharedLoggersStress(t *testing.T) {
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	const workers = 8
+	const iterations = 200
+
+	m := NewManagerWithTag("[root]")
+	stderr := NewStderrLogger()
+	slice := NewSliceLogger()
+	require.NoError(t, m.Add(stderr))
+	require.NoError(t, m.Add(slice))
+
+	var wg, started sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		started.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			sm := NewManagerWithTag(fmt.Sprintf("[worker%d]", w))
+			assert.NoError(t, sm.AddShared(stderr))
+			assert.NoError(t, sm.AddShared(slice))
+			for i := 0; i < iterations; i++ {
+				sm.Info("message", Int("i", i))
+				m.Errorf("from %d", w)
+				if i == iterations/4 {
+					started.Done()
+				}
+			}
+			assert.Empty(t, sm.Close())
+		}(w)
+	}
+
+	// the loggers get closed by someone else while they are in use
+	started.Wait()
+	assert.NoError(t, stderr.Close())
+	assert.NoError(t, slice.Close())
+	wg.Wait()
+	assert.Empty(t, m.Close())
+
+	assert.True(t, stderr.IsClosed())
+	assert.True(t, slice.IsClosed())
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// we make sure LogfmtFormatter implements Formatter
+var _ Formatter = (*LogfmtFormatter)(nil)
+
+// NewLogfmtFormatter creates and returns a formatter that encodes entries
+// as logfmt lines
+func NewLogfmtFormatter() Formatter {
+	return &LogfmtFormatter{
+		now: time.Now,
+	}
+}
+
+// LogfmtFormatter is a formatter that encodes entries as logfmt lines:
+//
+//	time=2019-05-04T10:30:00Z level=error seq=12 tag=[app] msg="not found" user_id=42
+//
+// The globals and fields are printed after the message, sorted by key.
+// Since the values containing new lines are quoted, MultilineEscape and
+// MultilineIndent have the same effect as MultilineRaw.
+// The exported fields must not be changed once the formatter is in use
+type LogfmtFormatter struct {
+	MessagePolicy
+
+	// now is used for the entries that don't have a time
+	now Clock
+}
+
+// Format returns the logfmt representation of an entry
+func (f *LogfmtFormatter) Format(e *Entry) ([]byte, error) {
+	p := f.MessagePolicy
+	if p.Multiline != MultilineSplit {
+		p.Multiline = MultilineRaw
+	}
+	return p.formatMessage(e, f.format)
+}
+
+// format returns the logfmt representation of an entry, once the
+// message policy has been applied
+func (f *LogfmtFormatter) format(e *Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("time=")
+	buf.WriteString(entryTime(e, f.now).UTC().Format(time.RFC3339Nano))
+	buf.WriteString(" level=")
+	buf.WriteString(strings.ToLower(e.Level.String()))
+	if e.Seq != 0 {
+		buf.WriteString(" seq=")
+		buf.WriteString(strconv.FormatUint(e.Seq, 10))
+	}
+	if e.Tag != "" {
+		buf.WriteString(" tag=")
+		buf.WriteString(logfmtValue(e.Tag))
+	}
+	buf.WriteString(" msg=")
+	buf.WriteString(logfmtValue(e.Message))
+
+	data := e.Data()
+	for _, k := range sortedKeys(data) {
+		buf.WriteByte(' ')
+		buf.WriteString(k)
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(data[k]))
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogfmtFormatter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2019, 5, 4, 10, 30, 0, 0, time.UTC)
+	newFormatter := func() Formatter {
+		f := NewLogfmtFormatter().(*LogfmtFormatter)
+		f.now = func() time.Time { return now }
+		return f
+	}
+
+	testCases := []struct {
+		description string
+		entry       *Entry
+		expected    string
+	}{
+		{
+			description: "entry without tag nor globals",
+			entry:       &Entry{Level: LevelInfo, Message: "message"},
+			expected:    "time=2019-05-04T10:30:00Z level=info msg=message\n",
+		},
+		{
+			description: "entry with a sequence number",
+			entry:       &Entry{Seq: 42, Level: LevelInfo, Message: "message"},
+			expected:    "time=2019-05-04T10:30:00Z level=info seq=42 msg=message\n",
+		},
+		{
+			description: "values are quoted when needed",
+			entry: &Entry{
+				Time:    time.Date(2021, 2, 3, 4, 5, 6, 0, time.FixedZone("UTC+1", 3600)),
+				Level:   LevelError,
+				Tag:     "[app][db]",
+				Message: "not found",
+				Globals: map[string]interface{}{"user_id": 42, "query": ""},
+				Fields:  map[string]interface{}{"error": errors.New("no rows")},
+			},
+			expected: `time=2021-02-03T03:05:06Z level=error tag=[app][db] msg="not found" error="no rows" query="" user_id=42` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := newFormatter().Format(tc.entry)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(data))
+		})
+	}
+}
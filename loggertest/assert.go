@@ -0,0 +1,179 @@
+package loggertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	logger "github.com/Nivl/go-logger"
+)
+
+// UpdateGoldenEnv is the environment variable to set to update the golden
+// files instead of comparing them:
+//
+//	LOGGERTEST_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "LOGGERTEST_UPDATE_GOLDEN"
+
+// Capture adds a MemoryLogger to the given manager, and removes it once
+// the test is over
+func Capture(t testing.TB, m logger.Manager) *MemoryLogger {
+	t.Helper()
+
+	mem := NewMemoryLogger()
+	if err := m.Add(mem); err != nil {
+		t.Fatalf("could not add the memory logger to the manager: %v", err)
+	}
+	t.Cleanup(func() {
+		m.Remove(mem.ID()) //nolint:errcheck
+	})
+	return mem
+}
+
+// AssertLogged checks that at least one entry of the given level has been
+// recorded with a message matching pattern.
+// pattern can either be a string, in which case the message has to
+// contain it, or a *regexp.Regexp.
+// Returns whether the assertion succeeded
+func AssertLogged(t testing.TB, mem *MemoryLogger, lvl logger.Level, pattern interface{}) bool {
+	t.Helper()
+
+	if len(find(mem, lvl, pattern)) == 0 {
+		t.Errorf("no %s entries matching %q have been logged.\n%s", lvl, pattern, dump(mem))
+		return false
+	}
+	return true
+}
+
+// AssertNotLogged checks that no entries of the given level have been
+// recorded with a message matching pattern.
+// See AssertLogged for the format of pattern.
+// Returns whether the assertion succeeded
+func AssertNotLogged(t testing.TB, mem *MemoryLogger, lvl logger.Level, pattern interface{}) bool {
+	t.Helper()
+
+	if len(find(mem, lvl, pattern)) > 0 {
+		t.Errorf("%s entries matching %q should not have been logged.\n%s", lvl, pattern, dump(mem))
+		return false
+	}
+	return true
+}
+
+// AssertFields checks that at least one entry of the given level, which
+// message matches pattern, contains all the given fields in its data.
+// See AssertLogged for the format of pattern.
+// Returns whether the assertion succeeded
+func AssertFields(t testing.TB, mem *MemoryLogger, lvl logger.Level, pattern interface{}, fields map[string]interface{}) bool {
+	t.Helper()
+
+	entries := find(mem, lvl, pattern)
+	for i := range entries {
+		if hasFields(&entries[i], fields) {
+			return true
+		}
+	}
+	t.Errorf("no %s entries matching %q have been logged with the fields %v.\n%s", lvl, pattern, fields, dump(mem))
+	return false
+}
+
+// AssertGolden checks that the text representation of all the recorded
+// entries matches the content of the given golden file.
+// The file is created or updated instead if the UpdateGoldenEnv
+// environment variable is set.
+// Returns whether the assertion succeeded
+func AssertGolden(t testing.TB, mem *MemoryLogger, path string) bool {
+	t.Helper()
+
+	var actual bytes.Buffer
+	entries := mem.Entries()
+	for i := range entries {
+		actual.WriteString(formatEntry(&entries[i]))
+	}
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := ioutil.WriteFile(path, actual.Bytes(), 0644); err != nil {
+			t.Fatalf("could not update the golden file %s: %v", path, err)
+		}
+		return true
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read the golden file %s: %v", path, err)
+	}
+	if !bytes.Equal(expected, actual.Bytes()) {
+		t.Errorf("the logs don't match the golden file %s.\nexpected:\n%s\nactual:\n%s", path, expected, actual.Bytes())
+		return false
+	}
+	return true
+}
+
+// find returns all the entries of the given level which message matches
+// pattern
+func find(mem *MemoryLogger, lvl logger.Level, pattern interface{}) []logger.Entry {
+	var match func(string) bool
+	switch p := pattern.(type) {
+	case *regexp.Regexp:
+		match = p.MatchString
+	case string:
+		match = func(msg string) bool { return strings.Contains(msg, p) }
+	default:
+		panic(fmt.Sprintf("unsupported pattern type %T", pattern))
+	}
+
+	return mem.Filter(func(e *logger.Entry) bool {
+		return e.Level == lvl && match(e.Message)
+	})
+}
+
+// hasFields returns whether the entry contains all the given fields
+func hasFields(e *logger.Entry, fields map[string]interface{}) bool {
+	data := e.Data()
+	for k, expected := range fields {
+		actual, ok := data[k]
+		if !ok || !reflect.DeepEqual(expected, actual) {
+			return false
+		}
+	}
+	return true
+}
+
+// dump returns the text representation of all the recorded entries, to
+// be used in the error messages
+func dump(mem *MemoryLogger) string {
+	entries := mem.Entries()
+	if len(entries) == 0 {
+		return "no entries have been logged"
+	}
+
+	var buf strings.Builder
+	buf.WriteString("logged entries:\n")
+	for i := range entries {
+		buf.WriteString("\t")
+		buf.WriteString(formatEntry(&entries[i]))
+	}
+	return buf.String()
+}
+
+// formatEntry returns a deterministic text representation of an entry
+func formatEntry(e *logger.Entry) string {
+	s := e.Level.String() + " "
+	if e.Tag != "" {
+		s += e.Tag + " "
+	}
+	s += e.Message
+	if data := e.Data(); len(data) > 0 {
+		// json.Marshal sorts the keys of the maps
+		globals, err := json.Marshal(data)
+		if err != nil {
+			globals = []byte(fmt.Sprintf("%v", data))
+		}
+		s += " " + string(globals)
+	}
+	return s + "\n"
+}
@@ -0,0 +1,97 @@
+package loggertest
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT is a testing.TB that records the failures instead of failing
+// the test
+type fakeT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	t.Parallel()
+
+	m := logger.NewManagerWithTag("[app]")
+	mem := Capture(t, m)
+	m.AddGlobalData("version", "1.0")
+	m.Info("starting")
+	m.NewSubManager("[db]").Error("connection lost")
+
+	t.Run("AssertLogged", func(t *testing.T) {
+		t.Parallel()
+		ft := &fakeT{TB: t}
+
+		assert.True(t, AssertLogged(ft, mem, logger.LevelError, "connection"))
+		assert.True(t, AssertLogged(ft, mem, logger.LevelInfo, regexp.MustCompile("^start")))
+		assert.Empty(t, ft.failures)
+
+		assert.False(t, AssertLogged(ft, mem, logger.LevelError, "starting"))
+		assert.False(t, AssertLogged(ft, mem, logger.LevelError, regexp.MustCompile("^lost")))
+		assert.Len(t, ft.failures, 2)
+		assert.Contains(t, ft.failures[0], "ERROR [app][db] connection lost")
+	})
+
+	t.Run("AssertNotLogged", func(t *testing.T) {
+		t.Parallel()
+		ft := &fakeT{TB: t}
+
+		assert.True(t, AssertNotLogged(ft, mem, logger.LevelDebug, "starting"))
+		assert.Empty(t, ft.failures)
+
+		assert.False(t, AssertNotLogged(ft, mem, logger.LevelInfo, "starting"))
+		assert.Len(t, ft.failures, 1)
+	})
+
+	t.Run("AssertFields", func(t *testing.T) {
+		t.Parallel()
+		ft := &fakeT{TB: t}
+
+		fields := map[string]interface{}{"version": "1.0"}
+		assert.True(t, AssertFields(ft, mem, logger.LevelInfo, "starting", fields))
+		assert.Empty(t, ft.failures)
+
+		fields = map[string]interface{}{"version": "2.0"}
+		assert.False(t, AssertFields(ft, mem, logger.LevelInfo, "starting", fields))
+		assert.Len(t, ft.failures, 1)
+	})
+
+	t.Run("AssertGolden", func(t *testing.T) {
+		t.Parallel()
+		ft := &fakeT{TB: t}
+
+		assert.True(t, AssertGolden(ft, mem, filepath.Join("testdata", "golden.log")))
+		assert.Empty(t, ft.failures)
+	})
+}
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+
+	m := logger.NewManager()
+	var mem *MemoryLogger
+	t.Run("logger is added", func(t *testing.T) {
+		mem = Capture(t, m)
+		m.Info("message")
+		assert.Equal(t, 1, mem.Len())
+	})
+
+	// The logger should have been removed once the subtest is over
+	m.Info("message")
+	assert.Equal(t, 1, mem.Len())
+	assert.True(t, mem.IsClosed())
+}
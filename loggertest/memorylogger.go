@@ -0,0 +1,184 @@
+// Package loggertest contains helpers to test code that uses loggers
+package loggertest
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// List of all errors
+var (
+	ErrTimeout = errors.New("timeout reached")
+)
+
+// we make sure MemoryLogger implements EntryLogger
+var _ logger.EntryLogger = (*MemoryLogger)(nil)
+
+// NewMemoryLogger creates and returns a logger that keeps all the entries
+// in memory
+func NewMemoryLogger() *MemoryLogger {
+	return &MemoryLogger{
+		id:      "memory-logger:" + uuid.New().String(),
+		updated: make(chan struct{}),
+	}
+}
+
+// MemoryLogger is a go-routine safe logger that keeps all the entries
+// in memory. Entries are kept after the logger has been closed.
+type MemoryLogger struct {
+	mu      sync.Mutex
+	id      string
+	entries []logger.Entry
+	closed  bool
+
+	// updated is closed and replaced every time an entry is added
+	updated chan struct{}
+}
+
+// ID returns the logger's unique ID
+func (l *MemoryLogger) ID() string {
+	return l.id
+}
+
+// Close marks the logger as closed. The entries are not removed
+func (l *MemoryLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	return nil
+}
+
+// IsClosed returns wether the logger is closed or not
+func (l *MemoryLogger) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// LogEntry records an entry
+func (l *MemoryLogger) LogEntry(e *logger.Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, *e)
+	close(l.updated)
+	l.updated = make(chan struct{})
+	return nil
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (l *MemoryLogger) Error(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelError)
+}
+
+// Info logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Println.
+func (l *MemoryLogger) Info(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelInfo)
+}
+
+// Debug logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Println.
+func (l *MemoryLogger) Debug(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelDebug)
+}
+
+// Log logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Println.
+func (l *MemoryLogger) Log(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelDefault)
+}
+
+// Entries returns a copy of all the recorded entries
+func (l *MemoryLogger) Entries() []logger.Entry {
+	return l.Filter(func(*logger.Entry) bool { return true })
+}
+
+// Filter returns a copy of all the recorded entries matching the given
+// predicate
+func (l *MemoryLogger) Filter(match func(e *logger.Entry) bool) []logger.Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := []logger.Entry{}
+	for i := range l.entries {
+		if match(&l.entries[i]) {
+			entries = append(entries, l.entries[i])
+		}
+	}
+	return entries
+}
+
+// ByLevel returns a copy of all the recorded entries of the given level
+func (l *MemoryLogger) ByLevel(lvl logger.Level) []logger.Entry {
+	return l.Filter(func(e *logger.Entry) bool {
+		return e.Level == lvl
+	})
+}
+
+// ByTag returns a copy of all the recorded entries which full tag starts
+// with the given prefix
+func (l *MemoryLogger) ByTag(prefix string) []logger.Entry {
+	return l.Filter(func(e *logger.Entry) bool {
+		return strings.HasPrefix(e.Tag, prefix)
+	})
+}
+
+// Messages returns the messages of all the recorded entries
+func (l *MemoryLogger) Messages() []string {
+	entries := l.Entries()
+	msgs := make([]string, len(entries))
+	for i := range entries {
+		msgs[i] = entries[i].Message
+	}
+	return msgs
+}
+
+// Len returns the number of recorded entries
+func (l *MemoryLogger) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// Reset removes all the recorded entries
+func (l *MemoryLogger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+}
+
+// WaitFor blocks until at least n entries have been recorded, or until
+// the timeout is reached.
+// Returns a copy of the recorded entries, and ErrTimeout if the timeout
+// has been reached
+func (l *MemoryLogger) WaitFor(n int, timeout time.Duration) ([]logger.Entry, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		l.mu.Lock()
+		count := len(l.entries)
+		updated := l.updated
+		l.mu.Unlock()
+
+		if count >= n {
+			return l.Entries(), nil
+		}
+
+		select {
+		case <-updated:
+		case <-timer.C:
+			return l.Entries(), ErrTimeout
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package loggertest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IDs are unique", func(t *testing.T) {
+		t.Parallel()
+		assert.NotEqual(t, NewMemoryLogger().ID(), NewMemoryLogger().ID())
+	})
+
+	t.Run("entries are kept after Close", func(t *testing.T) {
+		t.Parallel()
+		l := NewMemoryLogger()
+		l.Log("message\n")
+
+		require.NoError(t, l.Close())
+		assert.True(t, l.IsClosed())
+		assert.Equal(t, []string{"message"}, l.Messages())
+	})
+
+	t.Run("entries from a manager", func(t *testing.T) {
+		t.Parallel()
+		l := NewMemoryLogger()
+		m := logger.NewManagerWithTag("[app]")
+		require.NoError(t, m.Add(l))
+		sm := m.NewSubManager("[sub]")
+
+		m.Info("a")
+		sm.Error("b")
+		m.Debug("c")
+		require.Equal(t, 3, l.Len())
+
+		entries := l.Entries()
+		assert.Equal(t, logger.LevelInfo, entries[0].Level)
+		assert.Equal(t, "[app]", entries[0].Tag)
+		assert.Equal(t, "a", entries[0].Message)
+
+		errs := l.ByLevel(logger.LevelError)
+		require.Len(t, errs, 1)
+		assert.Equal(t, "b", errs[0].Message)
+
+		subs := l.ByTag("[app][sub]")
+		require.Len(t, subs, 1)
+		assert.Equal(t, "b", subs[0].Message)
+
+		debugs := l.Filter(func(e *logger.Entry) bool {
+			return e.Message == "c"
+		})
+		require.Len(t, debugs, 1)
+		assert.Equal(t, logger.LevelDebug, debugs[0].Level)
+
+		l.Reset()
+		assert.Empty(t, l.Entries())
+	})
+
+	t.Run("concurrent writes", func(t *testing.T) {
+		t.Parallel()
+		l := NewMemoryLogger()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.Info("message")
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 50, l.Len())
+	})
+
+	t.Run("WaitFor returns once enough entries are recorded", func(t *testing.T) {
+		t.Parallel()
+		l := NewMemoryLogger()
+
+		go func() {
+			for i := 0; i < 3; i++ {
+				l.Info("message")
+			}
+		}()
+
+		entries, err := l.WaitFor(3, time.Second)
+		require.NoError(t, err)
+		assert.Len(t, entries, 3)
+	})
+
+	t.Run("WaitFor times out", func(t *testing.T) {
+		t.Parallel()
+		l := NewMemoryLogger()
+		l.Info("message")
+
+		entries, err := l.WaitFor(2, 10*time.Millisecond)
+		assert.Equal(t, ErrTimeout, err)
+		assert.Len(t, entries, 1)
+	})
+}
@@ -0,0 +1,125 @@
+package loggertest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/google/uuid"
+)
+
+// we make sure TestingLogger implements EntryLogger
+var _ logger.EntryLogger = (*TestingLogger)(nil)
+
+// NewTestingLogger creates and returns a logger that writes through
+// t.Log. The logger becomes a no-op once the test is over
+func NewTestingLogger(t testing.TB) *TestingLogger {
+	l := &TestingLogger{
+		id: "testing-logger:" + uuid.New().String(),
+		t:  t,
+	}
+	// Logging after the end of a test makes it panic, which may happen
+	// if the code under test still has go-routines running
+	t.Cleanup(func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.testDone = true
+	})
+	return l
+}
+
+// TestingLogger is a go-routine safe logger that writes through t.Log,
+// so the logs are attached to the right test, and only printed when the
+// test fails or is run in verbose mode
+type TestingLogger struct {
+	// FailOnError makes the test fail when an entry is logged at the
+	// Error level. Must be set before using the logger
+	FailOnError bool
+
+	mu       sync.Mutex
+	id       string
+	t        testing.TB
+	closed   bool
+	testDone bool
+}
+
+// ID returns the logger's unique ID
+func (l *TestingLogger) ID() string {
+	return l.id
+}
+
+// Close makes the logger a no-op
+func (l *TestingLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	return nil
+}
+
+// IsClosed returns wether the logger is closed or not
+func (l *TestingLogger) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// LogEntry writes an entry through t.Log, or t.Error if FailOnError is
+// set and the entry is an error
+func (l *TestingLogger) LogEntry(e *logger.Entry) error {
+	l.t.Helper()
+	l.log(e)
+	return nil
+}
+
+// log writes an entry through t.Log, or t.Error if FailOnError is set
+// and the entry is an error
+func (l *TestingLogger) log(e *logger.Entry) {
+	l.t.Helper()
+
+	// We keep the lock while logging to make sure the test doesn't end
+	// in the meantime
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed || l.testDone {
+		return
+	}
+
+	msg := strings.TrimSuffix(formatEntry(e), "\n")
+	if l.FailOnError && e.Level == logger.LevelError {
+		l.t.Error(msg)
+		return
+	}
+	l.t.Log(msg)
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (l *TestingLogger) Error(msg string) {
+	l.t.Helper()
+	l.log(logger.NewEntry(msg, logger.LevelError))
+}
+
+// Info logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Println.
+func (l *TestingLogger) Info(msg string) {
+	l.t.Helper()
+	l.log(logger.NewEntry(msg, logger.LevelInfo))
+}
+
+// Debug logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Println.
+func (l *TestingLogger) Debug(msg string) {
+	l.t.Helper()
+	l.log(logger.NewEntry(msg, logger.LevelDebug))
+}
+
+// Log logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Println.
+func (l *TestingLogger) Log(msg string) {
+	l.t.Helper()
+	l.log(logger.NewEntry(msg, logger.LevelDefault))
+}
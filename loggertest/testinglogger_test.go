@@ -0,0 +1,88 @@
+package loggertest
+
+import (
+	"fmt"
+	"testing"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingT is a testing.TB that records the calls to Log and Error
+type recordingT struct {
+	testing.TB
+	logs     []string
+	errors   []string
+	cleanups []func()
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Log(args ...interface{}) {
+	t.logs = append(t.logs, fmt.Sprint(args...))
+}
+
+func (t *recordingT) Error(args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func (t *recordingT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+// end simulates the end of the test
+func (t *recordingT) end() {
+	for _, f := range t.cleanups {
+		f()
+	}
+}
+
+func TestTestingLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("entries are logged through t.Log", func(t *testing.T) {
+		t.Parallel()
+		rt := &recordingT{TB: t}
+		m := logger.NewManagerWithTag("[app]")
+		require.NoError(t, m.Add(NewTestingLogger(rt)))
+
+		m.Info("a")
+		m.Error("b")
+		assert.Equal(t, []string{"INFO [app] a", "ERROR [app] b"}, rt.logs)
+		assert.Empty(t, rt.errors)
+	})
+
+	t.Run("FailOnError fails the test on errors", func(t *testing.T) {
+		t.Parallel()
+		rt := &recordingT{TB: t}
+		l := NewTestingLogger(rt)
+		l.FailOnError = true
+
+		l.Info("a\n")
+		l.Error("b\n")
+		assert.Equal(t, []string{"INFO a"}, rt.logs)
+		assert.Equal(t, []string{"ERROR b"}, rt.errors)
+	})
+
+	t.Run("logger is a no-op after the end of the test", func(t *testing.T) {
+		t.Parallel()
+		rt := &recordingT{TB: t}
+		l := NewTestingLogger(rt)
+
+		rt.end()
+		l.Info("a")
+		assert.Empty(t, rt.logs)
+	})
+
+	t.Run("logger is a no-op once closed", func(t *testing.T) {
+		t.Parallel()
+		rt := &recordingT{TB: t}
+		l := NewTestingLogger(rt)
+
+		require.NoError(t, l.Close())
+		assert.True(t, l.IsClosed())
+		l.Info("a")
+		assert.Empty(t, rt.logs)
+	})
+}
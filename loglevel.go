@@ -2,28 +2,32 @@ package logger
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
-// logLevel is an helper type to hold all the different log levels
-type logLevel int
+// Level represents the level of a log entry
+type Level int
 
 // ALl the log levels
 const (
-	levelDefault logLevel = iota
-	levelDebug
-	levelInfo
-	levelError
+	LevelDefault Level = iota
+	LevelDebug
+	LevelInfo
+	LevelError
 )
 
-func (level logLevel) Tag() string {
+// Tag returns the tag prepended to the messages logged at this level
+func (level Level) Tag() string {
 	levelStr := ""
 
 	switch level {
-	case levelDebug:
+	case LevelDebug:
 		levelStr = "DEBUG"
-	case levelInfo:
+	case LevelInfo:
 		levelStr = "INFO"
-	case levelError:
+	case LevelError:
 		levelStr = "ERROR"
 	default:
 		return ""
@@ -31,3 +35,70 @@ func (level logLevel) Tag() string {
 
 	return fmt.Sprintf("[%s]", levelStr)
 }
+
+// String returns the name of the level
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "LOG"
+	}
+}
+
+// AtLeast returns whether the level is as severe as, or more severe
+// than, min.
+// From the least to the most severe: Debug, Info, Default, Error
+func (level Level) AtLeast(min Level) bool {
+	return level.severity() >= min.severity()
+}
+
+// severity returns the rank of the level, from the least to the most
+// severe
+func (level Level) severity() int {
+	switch level {
+	case LevelDebug:
+		return 0
+	case LevelInfo:
+		return 1
+	case LevelError:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (level Level) MarshalText() ([]byte, error) {
+	return []byte(level.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (level *Level) UnmarshalText(text []byte) error {
+	lvl, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*level = lvl
+	return nil
+}
+
+// ParseLevel returns the level matching the given name. The name is
+// case insensitive
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToUpper(name) {
+	case "DEBUG":
+		return LevelDebug, nil
+	case "INFO":
+		return LevelInfo, nil
+	case "ERROR":
+		return LevelError, nil
+	case "LOG", "DEFAULT":
+		return LevelDefault, nil
+	}
+	return LevelDefault, errors.Errorf("unknown level %q", name)
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelAtLeast(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, LevelError.AtLeast(LevelDebug))
+	assert.True(t, LevelError.AtLeast(LevelDefault))
+	assert.True(t, LevelDefault.AtLeast(LevelInfo))
+	assert.True(t, LevelInfo.AtLeast(LevelInfo))
+	assert.False(t, LevelDebug.AtLeast(LevelInfo))
+	assert.False(t, LevelDefault.AtLeast(LevelError))
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	for _, lvl := range []Level{LevelDebug, LevelInfo, LevelDefault, LevelError} {
+		parsed, err := ParseLevel(lvl.String())
+		require.NoError(t, err)
+		assert.Equal(t, lvl, parsed)
+	}
+
+	lvl, err := ParseLevel("debug")
+	require.NoError(t, err)
+	assert.Equal(t, LevelDebug, lvl)
+
+	_, err = ParseLevel("nope")
+	assert.Error(t, err)
+}
+
+func TestLevelJSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(LevelInfo)
+	require.NoError(t, err)
+	assert.Equal(t, `"INFO"`, string(data))
+
+	var lvl Level
+	require.NoError(t, json.Unmarshal([]byte(`"error"`), &lvl))
+	assert.Equal(t, LevelError, lvl)
+}
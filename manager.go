@@ -1,9 +1,12 @@
 package logger
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -16,6 +19,7 @@ import (
 // List of all errors
 var (
 	ErrAlreadyExist = errors.New("logger already added")
+	ErrClosed       = errors.New("manager is closed")
 )
 
 // Manager is an interface used to manage loggers
@@ -30,22 +34,54 @@ type Manager interface {
 	RemoveGlobalData(key string)
 
 	// Add adds a new logger
-	// returns ErrAlreadyExist if the logger has already been added
+	// returns ErrAlreadyExist if the logger has already been added,
+	// or ErrClosed if the manager is closed
 	Add(Logger) error
 
+	// AddShared adds a logger that is owned by someone else. The manager
+	// will never close it
+	// returns ErrAlreadyExist if the logger has already been added,
+	// or ErrClosed if the manager is closed
+	AddShared(Logger) error
+
 	// Remove safely removes a logger
 	// returns the logger and an error if the logger could not be safely remove.
 	// Upon errors the logger will be force removed from the manager
+	// The logger is only closed if no other managers own it. If it is
+	// not closed, a *SharedErr containing the other owners is returned
 	Remove(loggerID string) error
 
+	// Detach removes a logger without closing it
+	Detach(loggerID string)
+
+	// IsClosed returns whether the manager has been closed.
+	// The entries logged by a closed manager are sent to the fallback
+	// logger
+	IsClosed() bool
+
 	// Close safely removes all the loggers
 	// All submanagers will also be closed
 	Close() []error
 
+	// CloseContext safely removes all the loggers, and waits for them
+	// to be closed until ctx is done.
+	// All submanagers will also be closed
+	// returns a list of errors if a logger could not be safely removed,
+	// or didn't close in time.
+	CloseContext(ctx context.Context) []error
+
+	// Flush flushes the loggers implementing Flusher of the manager and
+	// all its submanagers, in parallel, and waits for them until ctx
+	// is done.
+	// returns a list of errors if a logger could not be flushed, or
+	// didn't flush in time.
+	Flush(ctx context.Context) []error
+
 	// NewSubManager creates a new manager that can have its own loggers.
 	// The tag of the current manager will be passed to the submanager.
 	// Calling a logging method on a submanager will trigger the same logging
 	// method on the parent.
+	// The submanager of a closed manager is closed.
 	NewSubManager(tag string) Manager
 
 	// SetTag adds a tag to the logs
@@ -57,6 +93,75 @@ type Manager interface {
 	// FullTag returns the full tag (including parents) of the manager
 	FullTag() string
 
+	// SetContext attaches a context to the manager. The context will be
+	// attached to all the entries created by the manager and its
+	// submanagers
+	SetContext(ctx context.Context)
+
+	// Context returns the context attached to the manager, or the one of
+	// its closest parent if the manager doesn't have any.
+	// Returns context.Background() if no context has been set
+	Context() context.Context
+
+	// OnSinkError sets the function called when a logger of the manager or
+	// of its submanagers fails to write an entry. A nil function makes
+	// the manager use the function of its parent.
+	// The function can log using FromContext(e.Context), whose entries
+	// don't have their errors reported. Logging with the manager itself
+	// would make a failing logger call the function endlessly
+	OnSinkError(SinkErrorFunc)
+
+	// SetClock sets the clock used to timestamp the entries of the manager
+	// and its submanagers. A nil clock makes the manager use the clock of
+	// its parent, or time.Now
+	SetClock(Clock)
+
+	// SetMessagePolicy sets the way the manager and its submanagers format
+	// the long and multi-line messages sent to the loggers that don't
+	// implement EntryLogger
+	SetMessagePolicy(MessagePolicy)
+
+	// ClearMessagePolicy removes the message policy of the manager, which
+	// will then use the one of its parent
+	ClearMessagePolicy()
+
+	// SetMinLevel sets the minimum level an entry must have to be logged
+	// by the manager and its submanagers
+	SetMinLevel(Level)
+
+	// ClearMinLevel removes the minimum level of the manager, which will
+	// then use the one of its parent
+	ClearMinLevel()
+
+	// MinLevel returns the minimum level an entry must have to be logged
+	// by the manager, which is the one of its closest parent if the
+	// manager doesn't have any.
+	// Returns LevelDebug if no levels have been set
+	MinLevel() Level
+
+	// Loggers returns the loggers of the manager, sorted by ID.
+	// The loggers of the parents are not included
+	Loggers() []Logger
+
+	// Children returns the submanagers of the manager, sorted by tag
+	Children() []Manager
+
+	// Parent returns the parent of the manager, or nil if the manager
+	// is not a submanager
+	Parent() Manager
+
+	// Globals returns a copy of the global data of the manager.
+	// The data of the parents are not included
+	Globals() map[string]interface{}
+
+	// Walk calls fn for the manager and all its submanagers, depth first.
+	// The walk stops as soon as fn returns false
+	Walk(fn func(Manager) bool)
+
+	// Dump returns a human-readable representation of the tree of
+	// managers, starting from the manager
+	Dump() string
+
 	// Errorf logs an error message
 	// Arguments are handled in the manner of fmt.Printf
 	Errorf(msg string, args ...interface{})
@@ -96,6 +201,10 @@ type Manager interface {
 	Log(args ...interface{})
 }
 
+// SinkErrorFunc is a function called when a logger fails to write an
+// entry. Only the loggers implementing EntryLogger can report errors
+type SinkErrorFunc func(l Logger, e *Entry, err error)
+
 // Err represents an error caused by a specific logger
 type Err struct {
 	error
@@ -107,14 +216,44 @@ var _ Manager = (*DefaultManager)(nil)
 
 // DefaultManager is a basic go-routine safe logger
 type DefaultManager struct {
+	// gen is the generation of the cache. It's accessed atomically and
+	// must be the first field to be 64-bit aligned on 32-bit platforms
+	gen   uint64
+	cache atomic.Value // *managerCache
+
+	// closed is set to 1 once the manager is closed. It's accessed
+	// atomically
+	closed int32
+
+	// loggerSet contains an immutable copy of loggers, sorted by ID.
+	// It's replaced every time loggers changes, which allows the entries
+	// to be sent to the loggers without holding any lock
+	loggerSet atomic.Value // []Logger
+
 	sync.RWMutex
 
 	id       string
 	globals  map[string]interface{}
 	loggers  map[string]Logger
+	shared   map[string]bool // loggers added with AddShared
 	parent   *DefaultManager
 	children map[string]*DefaultManager
 	tag      string
+	ctx      context.Context
+	clock    Clock
+
+	onSinkError SinkErrorFunc
+
+	minLevel    Level
+	hasMinLevel bool
+
+	messagePolicy    MessagePolicy
+	hasMessagePolicy bool
+
+	// detached is set for the managers of a subtree that has been
+	// detached from its parent, which doesn't invalidate or close them
+	// anymore
+	detached int32
 }
 
 // NewManager creates a new manager
@@ -127,6 +266,7 @@ func NewManagerWithTag(tag string) Manager {
 	return &DefaultManager{
 		id:       uuid.New().String(),
 		loggers:  map[string]Logger{},
+		shared:   map[string]bool{},
 		globals:  map[string]interface{}{},
 		children: map[string]*DefaultManager{},
 		tag:      tag,
@@ -136,64 +276,140 @@ func NewManagerWithTag(tag string) Manager {
 // AddGlobalData is used to add data that will be added to all logs
 func (m *DefaultManager) AddGlobalData(key string, value interface{}) {
 	m.Lock()
-	defer m.Unlock()
 	m.globals[key] = value
+	m.Unlock()
+
+	m.invalidate()
 }
 
 // RemoveGlobalData is used to remove data that are added to all logs
 func (m *DefaultManager) RemoveGlobalData(key string) {
 	m.Lock()
-	defer m.Unlock()
 	delete(m.globals, key)
+	m.Unlock()
+
+	m.invalidate()
 }
 
 // Add adds a logger
-// returns ErrAlreadyExist if the logger has already been added
+// returns ErrAlreadyExist if the logger has already been added,
+// or ErrClosed if the manager is closed
 func (m *DefaultManager) Add(l Logger) error {
+	return m.add(l, true)
+}
+
+// AddShared adds a logger that is owned by someone else. The manager
+// will never close it
+// returns ErrAlreadyExist if the logger has already been added,
+// or ErrClosed if the manager is closed
+func (m *DefaultManager) AddShared(l Logger) error {
+	return m.add(l, false)
+}
+
+func (m *DefaultManager) add(l Logger, owner bool) error {
 	m.Lock()
 	defer m.Unlock()
 
+	if m.IsClosed() {
+		return ErrClosed
+	}
+
 	if _, ok := m.loggers[l.ID()]; ok {
 		return ErrAlreadyExist
 	}
 
 	m.loggers[l.ID()] = l
+	if !owner {
+		m.shared[l.ID()] = true
+	}
+	acquireLogger(l, m, owner)
+	m.publishLoggers()
 	return nil
 }
 
 // Remove safely removes a logger
 // returns an Err struct if a logger could not be safely removed.
 // Upon errors the logger will be force removed from the manager
+// The logger is only closed if no other managers own it. If it is
+// not closed, a *SharedErr containing the other owners is returned
 func (m *DefaultManager) Remove(loggerID string) error {
-	m.Lock()
-	l, ok := m.loggers[loggerID]
-	delete(m.loggers, loggerID)
-	m.Unlock()
+	l, owner, ok := m.take(loggerID)
+	if !ok {
+		return nil
+	}
 
-	if ok && l != nil {
-		err := l.Close()
-		if err != nil {
-			return &Err{
-				error:  err,
-				Logger: l,
-			}
+	shouldClose, others := releaseLogger(l, m, owner)
+	if !shouldClose {
+		if len(others) > 0 {
+			return &SharedErr{Logger: l, Managers: others}
 		}
+		return nil
 	}
 
+	if err := l.Close(); err != nil {
+		return &Err{
+			error:  err,
+			Logger: l,
+		}
+	}
 	return nil
 }
 
+// Detach removes a logger without closing it
+func (m *DefaultManager) Detach(loggerID string) {
+	if l, owner, ok := m.take(loggerID); ok {
+		releaseLogger(l, m, owner)
+	}
+}
+
+// take removes a logger from the manager, and returns it with whether
+// the manager owned it
+func (m *DefaultManager) take(loggerID string) (l Logger, owner bool, ok bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	l, ok = m.loggers[loggerID]
+	if !ok {
+		return nil, false, false
+	}
+	owner = !m.shared[loggerID]
+	delete(m.loggers, loggerID)
+	delete(m.shared, loggerID)
+	m.publishLoggers()
+	return l, owner, true
+}
+
 // Close safely removes all the loggers
 // All submanagers will also be closed
 // returns a list of errors if a logger could not be safely removed.
 func (m *DefaultManager) Close() []error {
-	return m.closeFromParent(false)
+	return m.CloseContext(context.Background())
+}
+
+// CloseContext safely removes all the loggers, and waits for them
+// to be closed until ctx is done.
+// All submanagers will also be closed
+// returns a list of errors if a logger could not be safely removed,
+// or didn't close in time.
+func (m *DefaultManager) CloseContext(ctx context.Context) []error {
+	return m.closeFromParent(ctx, false)
+}
+
+// IsClosed returns whether the manager has been closed.
+// The entries logged by a closed manager are sent to the fallback
+// logger
+func (m *DefaultManager) IsClosed() bool {
+	return atomic.LoadInt32(&m.closed) == 1
 }
 
-func (m *DefaultManager) closeFromParent(fromParents bool) []error {
+func (m *DefaultManager) closeFromParent(ctx context.Context, fromParents bool) []error {
 	m.Lock()
+	atomic.StoreInt32(&m.closed, 1)
 	loggers := m.loggers
+	shared := m.shared
 	m.loggers = map[string]Logger{}
+	m.shared = map[string]bool{}
+	m.publishLoggers()
 
 	children := m.children
 	m.children = map[string]*DefaultManager{}
@@ -204,17 +420,18 @@ func (m *DefaultManager) closeFromParent(fromParents bool) []error {
 	}
 	m.Unlock()
 
-	var errs []error
-	// Close the loggers
-	for _, l := range loggers {
-		if err := l.Close(); err != nil {
-			errs = append(errs, &Err{error: err, Logger: l})
+	// Close the loggers that are not used by other managers
+	toClose := make([]Logger, 0, len(loggers))
+	for id, l := range loggers {
+		if shouldClose, _ := releaseLogger(l, m, !shared[id]); shouldClose {
+			toClose = append(toClose, l)
 		}
 	}
+	errs := runAll(ctx, toClose, Logger.Close)
 
 	// We close the children too
 	for _, c := range children {
-		if cErrs := c.closeFromParent(true); cErrs != nil {
+		if cErrs := c.closeFromParent(ctx, true); cErrs != nil {
 			errs = append(errs, cErrs...)
 		}
 	}
@@ -222,6 +439,90 @@ func (m *DefaultManager) closeFromParent(fromParents bool) []error {
 	return errs
 }
 
+// Flush flushes the loggers implementing Flusher of the manager and
+// all its submanagers, in parallel, and waits for them until ctx
+// is done.
+// returns a list of errors if a logger could not be flushed, or
+// didn't flush in time.
+func (m *DefaultManager) Flush(ctx context.Context) []error {
+	// A logger may be attached to more than one manager of the tree,
+	// but we only want to flush it once
+	seen := map[string]bool{}
+	var flushers []Logger
+	m.Walk(func(c Manager) bool {
+		for _, l := range c.Loggers() {
+			if _, ok := l.(Flusher); ok && !seen[l.ID()] {
+				seen[l.ID()] = true
+				flushers = append(flushers, l)
+			}
+		}
+		return true
+	})
+
+	return runAll(ctx, flushers, func(l Logger) error {
+		return l.(Flusher).Flush()
+	})
+}
+
+// runAll calls fn on all the loggers in parallel, and waits for all
+// the calls to return or for ctx to be done.
+// returns an Err for each call that failed, or that didn't return
+// before ctx was done
+func runAll(ctx context.Context, loggers []Logger, fn func(Logger) error) []error {
+	type result struct {
+		index int
+		err   error
+	}
+
+	// the channel is buffered so the go-routines that didn't return in
+	// time don't leak once they return
+	results := make(chan result, len(loggers))
+	for i, l := range loggers {
+		go func(i int, l Logger) {
+			results <- result{index: i, err: fn(l)}
+		}(i, l)
+	}
+
+	var errs []error
+	done := make([]bool, len(loggers))
+	for remaining := len(loggers); remaining > 0; remaining-- {
+		select {
+		case res := <-results:
+			done[res.index] = true
+			if res.err != nil {
+				errs = append(errs, &Err{error: res.err, Logger: loggers[res.index]})
+			}
+		case <-ctx.Done():
+			for i, l := range loggers {
+				if !done[i] {
+					err := errors.Wrap(ctx.Err(), "logger did not return in time")
+					errs = append(errs, &Err{error: err, Logger: l})
+				}
+			}
+			return errs
+		}
+	}
+	return errs
+}
+
+// detachClosed removes a closed logger without closing it again
+func (m *DefaultManager) detachClosed(loggerID string) {
+	m.Lock()
+	l, ok := m.loggers[loggerID]
+	// The logger may have been replaced in the meantime
+	if !ok || !l.IsClosed() {
+		m.Unlock()
+		return
+	}
+	owner := !m.shared[loggerID]
+	delete(m.loggers, loggerID)
+	delete(m.shared, loggerID)
+	m.publishLoggers()
+	m.Unlock()
+
+	releaseLogger(l, m, owner)
+}
+
 // removeChild removes a child manager without closing it
 func (m *DefaultManager) removeChild(id string) {
 	m.Lock()
@@ -229,10 +530,41 @@ func (m *DefaultManager) removeChild(id string) {
 	m.Unlock()
 }
 
+// detachFromParent removes the manager from the submanagers of its
+// parent without closing it. The manager keeps sending its entries to
+// the loggers of its parents, and keeps inheriting their settings, but
+// is not flushed or closed with them. Once one of its parents is closed,
+// its entries are sent to the fallback logger
+func (m *DefaultManager) detachFromParent() {
+	if m.parent == nil {
+		return
+	}
+	m.Walk(func(c Manager) bool {
+		atomic.StoreInt32(&c.(*DefaultManager).detached, 1)
+		return true
+	})
+	m.parent.removeChild(m.ID())
+}
+
+// hasClosedParent returns whether one of the parents of a detached
+// manager is closed. The other managers are closed with their parents
+func (m *DefaultManager) hasClosedParent() bool {
+	if atomic.LoadInt32(&m.detached) == 0 {
+		return false
+	}
+	for p := m.parent; p != nil; p = p.parent {
+		if p.IsClosed() {
+			return true
+		}
+	}
+	return false
+}
+
 // NewSubManager creates a new manager that can have its own loggers.
 // The tag of the current manager will be passed to the submanager.
 // Calling a logging method on a submanager will trigger the same logging
 // method on the parent.
+// The submanager of a closed manager is closed.
 func (m *DefaultManager) NewSubManager(tag string) Manager {
 	m.Lock()
 	defer m.Unlock()
@@ -240,6 +572,13 @@ func (m *DefaultManager) NewSubManager(tag string) Manager {
 	sm := NewManagerWithTag(tag)
 	df := sm.(*DefaultManager)
 	df.parent = m
+	df.detached = atomic.LoadInt32(&m.detached)
+
+	// A closed manager has no children, so the submanager is not attached
+	if m.IsClosed() {
+		df.closed = 1
+		return sm
+	}
 
 	m.children[sm.ID()] = df
 	return sm
@@ -248,9 +587,11 @@ func (m *DefaultManager) NewSubManager(tag string) Manager {
 // SetTag adds a tag to the logs
 func (m *DefaultManager) SetTag(tag string) {
 	m.Lock()
-	defer m.Unlock()
 
 	m.tag = tag
+	m.Unlock()
+
+	m.invalidate()
 }
 
 // Tag returns the tag of the manager
@@ -262,11 +603,7 @@ func (m *DefaultManager) Tag() string {
 
 // FullTag returns the full tag (including parents) of the manager
 func (m *DefaultManager) FullTag() string {
-	tag := m.Tag()
-	if m.parent != nil {
-		tag = m.parent.FullTag() + tag
-	}
-	return tag
+	return m.snapshot().fullTag
 }
 
 // ID returns the manager's unique ID
@@ -275,60 +612,251 @@ func (m *DefaultManager) ID() string {
 	return m.id
 }
 
-// Errorf logs an error message
-// Arguments are handled in the manner of fmt.Printf
-func (m *DefaultManager) Errorf(msg string, args ...interface{}) {
-	m.Error(fmt.Sprintf(msg, args...))
+// SetContext attaches a context to the manager. The context will be
+// attached to all the entries created by the manager and its submanagers
+func (m *DefaultManager) SetContext(ctx context.Context) {
+	m.Lock()
+
+	m.ctx = ctx
+	m.Unlock()
+
+	m.invalidate()
 }
 
-// Error logs an error message
-// Arguments are handled in the manner of fmt.Println.
-func (m *DefaultManager) Error(args ...interface{}) {
-	msg := m.format(fmt.Sprintln(args...))
-	m.error(msg)
+// Context returns the context attached to the manager, or the one of
+// its closest parent if the manager doesn't have any.
+// Returns context.Background() if no context has been set
+func (m *DefaultManager) Context() context.Context {
+	return m.snapshot().ctx
+}
+
+// OnSinkError sets the function called when a logger of the manager or
+// of its submanagers fails to write an entry. A nil function makes
+// the manager use the function of its parent.
+// The function can log using FromContext(e.Context), whose entries
+// don't have their errors reported. Logging with the manager itself
+// would make a failing logger call the function endlessly
+func (m *DefaultManager) OnSinkError(fn SinkErrorFunc) {
+	m.Lock()
+	m.onSinkError = fn
+	m.Unlock()
+
+	m.invalidate()
+}
+
+// SetClock sets the clock used to timestamp the entries of the manager
+// and its submanagers. A nil clock makes the manager use the clock of
+// its parent, or time.Now
+func (m *DefaultManager) SetClock(clock Clock) {
+	m.Lock()
+
+	m.clock = clock
+	m.Unlock()
+
+	m.invalidate()
+}
+
+// SetMessagePolicy sets the way the manager and its submanagers format
+// the long and multi-line messages sent to the loggers that don't
+// implement EntryLogger.
+// The messages are printed as they are if no policies have been set
+func (m *DefaultManager) SetMessagePolicy(p MessagePolicy) {
+	m.Lock()
+
+	m.messagePolicy = p
+	m.hasMessagePolicy = true
+	m.Unlock()
+
+	m.invalidate()
+}
+
+// ClearMessagePolicy removes the message policy of the manager, which
+// will then use the one of its parent
+func (m *DefaultManager) ClearMessagePolicy() {
+	m.Lock()
+
+	m.messagePolicy = MessagePolicy{}
+	m.hasMessagePolicy = false
+	m.Unlock()
+
+	m.invalidate()
+}
+
+// SetMinLevel sets the minimum level an entry must have to be logged
+// by the manager and its submanagers
+func (m *DefaultManager) SetMinLevel(lvl Level) {
+	m.Lock()
+
+	m.minLevel = lvl
+	m.hasMinLevel = true
+	m.Unlock()
+
+	m.invalidate()
+}
+
+// ClearMinLevel removes the minimum level of the manager, which will
+// then use the one of its parent
+func (m *DefaultManager) ClearMinLevel() {
+	m.Lock()
+
+	m.minLevel = LevelDefault
+	m.hasMinLevel = false
+	m.Unlock()
+
+	m.invalidate()
+}
+
+// MinLevel returns the minimum level an entry must have to be logged
+// by the manager, which is the one of its closest parent if the
+// manager doesn't have any.
+// Returns LevelDebug if no levels have been set
+func (m *DefaultManager) MinLevel() Level {
+	return m.snapshot().minLevel
+}
+
+// Loggers returns the loggers of the manager, sorted by ID.
+// The loggers of the parents are not included
+func (m *DefaultManager) Loggers() []Logger {
+	// The snapshot is shared, so we return a copy
+	return append([]Logger{}, m.loggerSnapshot()...)
+}
+
+// loggerSnapshot returns the current loggers of the manager, sorted by
+// ID. The returned slice must not be modified
+func (m *DefaultManager) loggerSnapshot() []Logger {
+	loggers, _ := m.loggerSet.Load().([]Logger)
+	return loggers
 }
 
-func (m *DefaultManager) error(msg string) {
+// publishLoggers replaces the snapshot of the loggers.
+// It must be called while holding the write lock
+func (m *DefaultManager) publishLoggers() {
+	loggers := make([]Logger, 0, len(m.loggers))
+	for _, l := range m.loggers {
+		loggers = append(loggers, l)
+	}
+	sort.Slice(loggers, func(i, j int) bool {
+		return loggers[i].ID() < loggers[j].ID()
+	})
+	m.loggerSet.Store(loggers)
+}
+
+// Children returns the submanagers of the manager, sorted by tag
+func (m *DefaultManager) Children() []Manager {
+	m.RLock()
+	children := make([]Manager, 0, len(m.children))
+	for _, c := range m.children {
+		children = append(children, c)
+	}
+	m.RUnlock()
+
+	// The IDs are used to keep the order stable when tags are identical
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].Tag() != children[j].Tag() {
+			return children[i].Tag() < children[j].Tag()
+		}
+		return children[i].ID() < children[j].ID()
+	})
+	return children
+}
+
+// Parent returns the parent of the manager, or nil if the manager
+// is not a submanager
+func (m *DefaultManager) Parent() Manager {
+	// we don't want to return a nil *DefaultManager wrapped in a
+	// non-nil interface
+	if m.parent == nil {
+		return nil
+	}
+	return m.parent
+}
+
+// Globals returns a copy of the global data of the manager.
+// The data of the parents are not included
+func (m *DefaultManager) Globals() map[string]interface{} {
 	m.RLock()
 	defer m.RUnlock()
 
-	// we send the log to the parent's logger first
-	if m.parent != nil {
-		m.parent.error(msg)
+	globals := make(map[string]interface{}, len(m.globals))
+	for k, v := range m.globals {
+		globals[k] = v
 	}
+	return globals
+}
 
-	for _, l := range m.loggers {
-		l.Error(msg)
+// Walk calls fn for the manager and all its submanagers, depth first.
+// The walk stops as soon as fn returns false
+func (m *DefaultManager) Walk(fn func(Manager) bool) {
+	walk(m, fn)
+}
+
+// walk walks the tree of managers, and returns false if the walk has
+// been stopped
+func walk(m Manager, fn func(Manager) bool) bool {
+	if !fn(m) {
+		return false
 	}
+	for _, c := range m.Children() {
+		if !walk(c, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Dump returns a human-readable representation of the tree of
+// managers, starting from the manager
+func (m *DefaultManager) Dump() string {
+	var buf strings.Builder
+	dump(&buf, m, 0)
+	return buf.String()
+}
+
+func dump(buf *strings.Builder, m Manager, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	tag := m.Tag()
+	if tag == "" {
+		tag = "(no tag)"
+	}
+	fmt.Fprintf(buf, "%s%s id=%s min_level=%s\n", indent, tag, m.ID(), m.MinLevel())
+
+	for _, l := range m.Loggers() {
+		fmt.Fprintf(buf, "%s  - logger %s\n", indent, l.ID())
+	}
+	globals := m.Globals()
+	for _, k := range sortedKeys(globals) {
+		fmt.Fprintf(buf, "%s  - global %s=%v\n", indent, k, globals[k])
+	}
+	for _, c := range m.Children() {
+		dump(buf, c, depth+1)
+	}
+}
+
+// Errorf logs an error message
+// Arguments are handled in the manner of fmt.Printf
+func (m *DefaultManager) Errorf(msg string, args ...interface{}) {
+	m.logf(LevelError, msg, args, false)
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (m *DefaultManager) Error(args ...interface{}) {
+	m.log(LevelError, args, false)
 }
 
 // Infof logs a message that may be helpful, but isn’t essential,
 // for troubleshooting
 // Arguments are handled in the manner of fmt.Printf
 func (m *DefaultManager) Infof(msg string, args ...interface{}) {
-	m.Info(fmt.Sprintf(msg, args...))
+	m.logf(LevelInfo, msg, args, false)
 }
 
 // Info logs a message that may be helpful, but isn’t essential,
 // for troubleshooting
 // Arguments are handled in the manner of fmt.Println.
 func (m *DefaultManager) Info(args ...interface{}) {
-	msg := m.format(fmt.Sprintln(args...))
-	m.info(msg)
-}
-
-func (m *DefaultManager) info(msg string) {
-	m.RLock()
-	defer m.RUnlock()
-
-	// we send the log to the parent's logger first
-	if m.parent != nil {
-		m.parent.info(msg)
-	}
-
-	for _, l := range m.loggers {
-		l.Info(msg)
-	}
+	m.log(LevelInfo, args, false)
 }
 
 // Debugf logs a message that is intended for use in a development
@@ -336,7 +864,7 @@ func (m *DefaultManager) info(msg string) {
 // software
 // Arguments are handled in the manner of fmt.Printf
 func (m *DefaultManager) Debugf(msg string, args ...interface{}) {
-	m.Debug(fmt.Sprintf(msg, args...))
+	m.logf(LevelDebug, msg, args, false)
 }
 
 // Debug logs a message that is intended for use in a development
@@ -344,80 +872,110 @@ func (m *DefaultManager) Debugf(msg string, args ...interface{}) {
 // software
 // Arguments are handled in the manner of fmt.Println.
 func (m *DefaultManager) Debug(args ...interface{}) {
-	msg := m.format(fmt.Sprintln(args...))
-	m.debug(msg)
-}
-
-func (m *DefaultManager) debug(msg string) {
-	m.RLock()
-	defer m.RUnlock()
-
-	// we send the log to the parent's logger first
-	if m.parent != nil {
-		m.parent.debug(msg)
-	}
-
-	for _, l := range m.loggers {
-		l.Debug(msg)
-	}
+	m.log(LevelDebug, args, false)
 }
 
 // Logf logs a message that might result a failure
 // Arguments are handled in the manner of fmt.Printf
 func (m *DefaultManager) Logf(msg string, args ...interface{}) {
-	m.Log(fmt.Sprintf(msg, args...))
+	m.logf(LevelDefault, msg, args, false)
 }
 
 // Log logs a message that might result a failure
 // Arguments are handled in the manner of fmt.Println.
 func (m *DefaultManager) Log(args ...interface{}) {
-	msg := m.format(fmt.Sprintln(args...))
-	m.log(msg)
+	m.log(LevelDefault, args, false)
 }
 
-func (m *DefaultManager) log(msg string) {
-	m.RLock()
-	defer m.RUnlock()
-
-	// we send the log to the parent's logger first
-	if m.parent != nil {
-		m.parent.log(msg)
+// log formats the arguments in the manner of fmt.Println, and sends
+// them to the loggers.
+// The write errors of unreported entries are not sent to the sink error
+// function
+func (m *DefaultManager) log(lvl Level, args []interface{}, unreported bool) {
+	// We don't want to pay for the formatting if the entry is dropped
+	if !m.enabled(lvl) {
+		return
 	}
+	args, fields := splitFields(args)
+	m.write(lvl, fmt.Sprintln(args...), fields, unreported)
+}
 
-	for _, l := range m.loggers {
-		l.Log(msg)
+// logf formats the arguments in the manner of fmt.Printf, and sends
+// them to the loggers
+func (m *DefaultManager) logf(lvl Level, msg string, args []interface{}, unreported bool) {
+	if !m.enabled(lvl) {
+		return
 	}
+	args, fields := splitFields(args)
+	m.write(lvl, fmt.Sprintln(fmt.Sprintf(msg, args...)), fields, unreported)
 }
 
-func (m *DefaultManager) format(msg string) string {
-	tag := m.FullTag()
-	if tag != "" {
-		msg = tag + " " + msg
-	}
+// enabled returns whether an entry of the given level should be logged.
+// The level of the manager that created the entry is the only one
+// that matters, the parents only forward the entry to their loggers
+func (m *DefaultManager) enabled(lvl Level) bool {
+	return lvl.AtLeast(m.MinLevel())
+}
 
-	if msg[len(msg)-1] != '\n' {
-		return msg + "\n"
+// write builds an entry out of the given message and sends it to all
+// the loggers of the manager and its parents
+func (m *DefaultManager) write(lvl Level, msg string, fields []Field, unreported bool) {
+	c := m.snapshot()
+	e := &Entry{
+		Time:       c.clock(),
+		Seq:        nextSequence(),
+		Level:      lvl,
+		Message:    strings.TrimSuffix(msg, "\n"),
+		Tag:        c.fullTag,
+		Tags:       c.tags,
+		Globals:    c.globals,
+		Fields:     fieldValues(fields),
+		Context:    c.ctx,
+		unreported: unreported,
 	}
 
-	globals := m.allGlobals()
-	if len(globals) > 0 {
-		jsonGlobals, err := json.Marshal(globals)
-		if err != nil {
-			panic(errors.Wrap(err, "could not encode the globals to JSON"))
+	// The entry is only formatted if at least one logger needs it
+	var formatted *string
+	format := func() string {
+		if formatted == nil {
+			s := c.format(e, msg)
+			formatted = &s
 		}
-		msg += string(jsonGlobals) + "\n"
+		return *formatted
 	}
 
-	return msg
+	// The loggers of a closed manager have been removed, but we don't
+	// want to lose the entry
+	if m.IsClosed() || m.hasClosedParent() {
+		if l := FallbackLogger(); l != nil {
+			_ = writeEntry(l, e, format) //nolint:errcheck
+		}
+		return
+	}
+	m.dispatch(e, format)
 }
 
-func (m *DefaultManager) allGlobals() map[string]interface{} {
-	globals := map[string]interface{}{}
+// dispatch sends an entry to the loggers of the manager and of its
+// parents. No locks are held while the loggers are called, so a slow
+// logger doesn't block the other calls to the manager, and loggers can
+// safely call the manager
+func (m *DefaultManager) dispatch(e *Entry, msg func() string) {
+	// we send the log to the parent's logger first
 	if m.parent != nil {
-		globals = m.parent.allGlobals()
+		m.parent.dispatch(e, msg)
 	}
-	for k, v := range m.globals {
-		globals[k] = v
+
+	for _, l := range m.loggerSnapshot() {
+		// A logger may have been closed by another manager or by the
+		// user, in which case it can't be used anymore
+		if l.IsClosed() {
+			m.detachClosed(l.ID())
+			continue
+		}
+		if err := writeEntry(l, e, msg); err != nil && !e.unreported {
+			if fn := m.snapshot().onSinkError; fn != nil {
+				m.reportSinkError(fn, l, e, err)
+			}
+		}
 	}
-	return globals
 }
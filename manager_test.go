@@ -1,9 +1,13 @@
 package logger
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -414,3 +418,254 @@ func TestManagerLog(t *testing.T) {
 		require.Len(t, l2.data, 1, "no logs should have been added")
 	})
 }
+
+type testContextKey struct{}
+
+func TestManagerContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Context() returns context.Background() by default", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		assert.Equal(t, context.Background(), m.Context())
+	})
+
+	t.Run("context gets set by SetContext()", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.WithValue(context.Background(), testContextKey{}, "value")
+		m := NewManager()
+		m.SetContext(ctx)
+		assert.Equal(t, ctx, m.Context())
+	})
+
+	t.Run("parents context gets returned by Context()", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.WithValue(context.Background(), testContextKey{}, "value")
+		m := NewManager()
+		m.SetContext(ctx)
+		sm := m.NewSubManager("child")
+		assert.Equal(t, ctx, sm.Context())
+	})
+}
+
+func TestManagerMinLevel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MinLevel() returns LevelDebug by default", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, LevelDebug, NewManager().MinLevel())
+	})
+
+	t.Run("parents level gets returned by MinLevel()", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		sm := m.NewSubManager("child")
+
+		m.SetMinLevel(LevelError)
+		assert.Equal(t, LevelError, sm.MinLevel())
+
+		sm.SetMinLevel(LevelInfo)
+		assert.Equal(t, LevelInfo, sm.MinLevel())
+
+		sm.ClearMinLevel()
+		assert.Equal(t, LevelError, sm.MinLevel())
+	})
+
+	t.Run("entries below the level are dropped", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		l := NewSliceLogger().(*SliceLogger)
+		require.NoError(t, m.Add(l))
+		m.SetMinLevel(LevelInfo)
+
+		m.Debug("a")
+		m.Info("b")
+		m.Log("c")
+		m.Error("d")
+		assert.Equal(t, []string{"[INFO]b\n", "c\n", "[ERROR]d\n"}, l.data)
+	})
+
+	t.Run("the level of the submanager is used", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager()
+		l := NewSliceLogger().(*SliceLogger)
+		require.NoError(t, m.Add(l))
+		m.SetMinLevel(LevelError)
+
+		sm := m.NewSubManager("[child]")
+		sm.SetMinLevel(LevelDebug)
+
+		m.Debug("a")
+		sm.Debug("b")
+		assert.Equal(t, []string{"[DEBUG][child] b\n"}, l.data)
+	})
+}
+
+func TestManagerIntrospection(t *testing.T) {
+	t.Parallel()
+
+	newTree := func() (m, db, web Manager) {
+		m = NewManagerWithTag("[app]")
+		db = m.NewSubManager("[db]")
+		web = m.NewSubManager("[http]")
+		return m, db, web
+	}
+
+	t.Run("Loggers() returns the loggers sorted by ID", func(t *testing.T) {
+		t.Parallel()
+		m, db, _ := newTree()
+		l1 := NewSliceLogger()
+		l2 := &SliceLogger{id: "a-logger"}
+		require.NoError(t, m.Add(l1))
+		require.NoError(t, m.Add(l2))
+
+		assert.Equal(t, []Logger{l2, l1}, m.Loggers())
+		assert.Empty(t, db.Loggers(), "the loggers of the parent should not be returned")
+	})
+
+	t.Run("Children() and Parent()", func(t *testing.T) {
+		t.Parallel()
+		m, db, web := newTree()
+
+		assert.Equal(t, []Manager{db, web}, m.Children())
+		assert.Equal(t, m, db.Parent())
+		assert.Nil(t, m.Parent())
+	})
+
+	t.Run("Globals() returns a copy", func(t *testing.T) {
+		t.Parallel()
+		m, db, _ := newTree()
+		m.AddGlobalData("key", "value")
+		db.AddGlobalData("db", "value")
+
+		globals := db.Globals()
+		assert.Equal(t, map[string]interface{}{"db": "value"}, globals)
+
+		globals["new"] = "value"
+		assert.Len(t, db.Globals(), 1, "the data of the manager should not have changed")
+	})
+
+	t.Run("Walk() walks the tree depth first", func(t *testing.T) {
+		t.Parallel()
+		m, db, web := newTree()
+		sub := db.NewSubManager("[sub]")
+
+		var visited []Manager
+		m.Walk(func(c Manager) bool {
+			visited = append(visited, c)
+			return true
+		})
+		assert.Equal(t, []Manager{m, db, sub, web}, visited)
+
+		visited = nil
+		m.Walk(func(c Manager) bool {
+			visited = append(visited, c)
+			return c != db
+		})
+		assert.Equal(t, []Manager{m, db}, visited, "the walk should have stopped")
+	})
+
+	t.Run("Dump() renders the tree", func(t *testing.T) {
+		t.Parallel()
+		m, db, _ := newTree()
+		require.NoError(t, db.Add(NewSliceLogger()))
+		db.AddGlobalData("key", "value")
+		m.SetMinLevel(LevelInfo)
+
+		expected := fmt.Sprintf("[app] id=%s min_level=INFO\n", m.ID()) +
+			fmt.Sprintf("  [db] id=%s min_level=INFO\n", db.ID()) +
+			"    - logger slice-logger\n" +
+			"    - global key=value\n"
+		assert.Contains(t, m.Dump(), expected)
+	})
+}
+
+func TestManagerOnSinkError(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager()
+	sm := m.NewSubManager("[sub]")
+	failing := &failingLogger{err: errors.New("disk full")}
+	require.NoError(t, sm.Add(failing))
+	require.NoError(t, m.Add(&entrySliceLogger{SliceLogger: SliceLogger{id: "working"}}))
+
+	// errors are dropped when there's no handler
+	assert.NotPanics(t, func() {
+		sm.Info("message")
+	})
+
+	type sinkError struct {
+		logger string
+		msg    string
+		err    error
+	}
+	var errs []sinkError
+	m.OnSinkError(func(l Logger, e *Entry, err error) {
+		errs = append(errs, sinkError{l.ID(), e.Message, err})
+	})
+
+	sm.Info("message")
+	require.Len(t, errs, 1, "the handler of the parent should have been used")
+	assert.Equal(t, sinkError{failing.ID(), "message", failing.err}, errs[0])
+
+	var ownErrs int
+	sm.OnSinkError(func(l Logger, e *Entry, err error) {
+		ownErrs++
+	})
+	sm.Info("message")
+	assert.Equal(t, 1, ownErrs, "the handler of the manager should have been used")
+	assert.Len(t, errs, 1)
+
+	sm.OnSinkError(nil)
+	sm.Info("message")
+	assert.Len(t, errs, 2, "the handler of the parent should have been used")
+}
+
+func TestManagerOnSinkErrorReentrancy(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager()
+	sm := m.NewSubManager("[sub]")
+	ctx := context.WithValue(context.Background(), testContextKey{}, "value")
+	sm.SetContext(ctx)
+	working := &entrySliceLogger{SliceLogger: SliceLogger{id: "working"}}
+	require.NoError(t, m.Add(working))
+	require.NoError(t, m.Add(&failingLogger{SliceLogger: SliceLogger{id: "failing"}, err: errors.New("disk full")}))
+
+	var calls int32
+	m.OnSinkError(func(l Logger, e *Entry, err error) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, "value", e.Context.Value(testContextKey{}), "the context of the entry should be kept")
+
+		// the entries also fail to be written, even when they're logged
+		// from another goroutine
+		FromContext(e.Context).Errorf("could not write to %s: %v", l.ID(), err)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			FromContext(e.Context).Error("from a goroutine")
+		}()
+		<-done
+	})
+
+	sm.Info("message")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "the errors of the handler should not be reported")
+	require.Len(t, working.entries, 3, "the entries of the handler should have been logged")
+	// the failing logger is called first
+	assert.Equal(t, "could not write to failing: disk full", working.entries[0].Message)
+	assert.Equal(t, "from a goroutine", working.entries[1].Message)
+	assert.Equal(t, "message", working.entries[2].Message)
+	assert.Equal(t, ctx, working.entries[2].Context, "the entry shared with the loggers should not change")
+
+	// only the entries of the handler are not reported
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sm.Info("message")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(11), atomic.LoadInt32(&calls), "every entry should have reported its error")
+}
@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// bufferPool contains the buffers used to format the entries
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// managerCache contains everything a manager inherits from its parents,
+// so it doesn't have to walk and lock the tree every time an entry
+// is logged.
+// A cache is never modified once built
+type managerCache struct {
+	gen uint64
+	// parent is the cache of the parent used to build the cache
+	parent *managerCache
+
+	fullTag  string
+	tags     []string
+	ctx      context.Context
+	clock    Clock
+	minLevel Level
+
+	onSinkError   SinkErrorFunc
+	messagePolicy MessagePolicy
+
+	// globals contains the globals of the manager and of its parents
+	globals map[string]interface{}
+	// encodedGlobals contains the JSON representation of globals
+	encodedGlobals []byte
+}
+
+// snapshot returns the cache of the manager, rebuilding it if it has
+// been invalidated
+func (m *DefaultManager) snapshot() *managerCache {
+	gen := atomic.LoadUint64(&m.gen)
+
+	// The managers of a detached subtree are not invalidated by the
+	// parent of the subtree, so they check the cache of their parent
+	// themselves
+	var parent *managerCache
+	if m.parent != nil && atomic.LoadInt32(&m.detached) == 1 {
+		parent = m.parent.snapshot()
+	}
+	if c, ok := m.cache.Load().(*managerCache); ok && c.gen == gen && (parent == nil || c.parent == parent) {
+		return c
+	}
+
+	c := &managerCache{
+		gen:      gen,
+		ctx:      context.Background(),
+		clock:    time.Now,
+		minLevel: LevelDebug,
+	}
+	var parentGlobals map[string]interface{}
+	if m.parent != nil {
+		p := parent
+		if p == nil {
+			p = m.parent.snapshot()
+		}
+		c.parent = p
+		c.fullTag = p.fullTag
+		c.tags = p.tags
+		c.ctx = p.ctx
+		c.clock = p.clock
+		c.minLevel = p.minLevel
+		c.onSinkError = p.onSinkError
+		c.messagePolicy = p.messagePolicy
+		parentGlobals = p.globals
+	}
+
+	m.RLock()
+	c.fullTag += m.tag
+	if m.tag != "" {
+		// we copy the tags of the parent since its slice is shared, and
+		// cap the slice so appending to it never changes the cache
+		tags := append(append(make([]string, 0, len(c.tags)+1), c.tags...), m.tag)
+		c.tags = tags[:len(tags):len(tags)]
+	}
+	if m.ctx != nil {
+		c.ctx = m.ctx
+	}
+	if m.clock != nil {
+		c.clock = m.clock
+	}
+	if m.hasMinLevel {
+		c.minLevel = m.minLevel
+	}
+	if m.onSinkError != nil {
+		c.onSinkError = m.onSinkError
+	}
+	if m.hasMessagePolicy {
+		c.messagePolicy = m.messagePolicy
+	}
+	c.globals = make(map[string]interface{}, len(parentGlobals)+len(m.globals))
+	for k, v := range parentGlobals {
+		c.globals[k] = v
+	}
+	for k, v := range m.globals {
+		c.globals[k] = v
+	}
+	m.RUnlock()
+
+	if len(c.globals) > 0 {
+		c.encodedGlobals = encodeData(c.globals)
+	}
+
+	// If the cache got invalidated while we were building it, the
+	// generation won't match and the cache will be rebuilt next time
+	m.cache.Store(c)
+	return c
+}
+
+// invalidate invalidates the cache of the manager and of all its
+// submanagers. It must be called without holding the lock of the manager
+func (m *DefaultManager) invalidate() {
+	// The parent must be invalidated before its children, so a child
+	// never rebuilds its cache using the stale cache of its parent
+	atomic.AddUint64(&m.gen, 1)
+
+	// The lock of the manager is released before the children are
+	// invalidated, since a closing child locks itself, then its parent
+	m.RLock()
+	children := make([]*DefaultManager, 0, len(m.children))
+	for _, c := range m.children {
+		children = append(children, c)
+	}
+	m.RUnlock()
+
+	for _, c := range children {
+		c.invalidate()
+	}
+}
+
+// format returns the text version of an entry, msg being the raw message
+// of the entry
+func (c *managerCache) format(e *Entry, msg string) string {
+	if c.messagePolicy == (MessagePolicy{}) {
+		return c.formatText(e, msg)
+	}
+
+	// formatText never fails
+	data, _ := c.messagePolicy.formatMessage(e, func(e *Entry) ([]byte, error) { //nolint:errcheck
+		return []byte(c.formatText(e, e.Message)), nil
+	})
+	return string(data)
+}
+
+// formatText returns the text version of an entry, using msg as message
+func (c *managerCache) formatText(e *Entry, msg string) string {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer bufferPool.Put(buf)
+	buf.Reset()
+
+	if e.Tag != "" {
+		buf.WriteString(e.Tag)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(msg)
+	if msg == "" || msg[len(msg)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	// The globals have already been encoded, unless the entry has its
+	// own fields
+	encoded := c.encodedGlobals
+	if len(e.Fields) > 0 {
+		encoded = encodeData(e.Data())
+	}
+	if len(encoded) > 0 {
+		buf.Write(encoded)
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
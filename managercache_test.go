@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerCacheInvalidation(t *testing.T) {
+	t.Parallel()
+
+	m := NewManagerWithTag("[app]")
+	sm := m.NewSubManager("[db]")
+	ssm := sm.NewSubManager("[query]")
+	l := &entrySliceLogger{}
+	require.NoError(t, m.Add(l))
+
+	ssm.Info("first")
+	require.Len(t, l.entries, 1, "no entries added")
+	assert.Equal(t, "[app][db][query]", l.entries[0].Tag)
+	assert.Empty(t, l.entries[0].Globals)
+
+	// changes made to a parent should be visible to all its submanagers
+	m.SetTag("[api]")
+	m.AddGlobalData("version", 2)
+	sm.AddGlobalData("table", "users")
+	m.SetMinLevel(LevelInfo)
+
+	ssm.Debug("dropped")
+	ssm.Info("second")
+	require.Len(t, l.entries, 2, "no entries added")
+	e := l.entries[1]
+	assert.Equal(t, "[api][db][query]", e.Tag)
+	assert.Equal(t, []string{"[api]", "[db]", "[query]"}, e.Tags)
+	assert.Equal(t, map[string]interface{}{"version": 2, "table": "users"}, e.Globals)
+
+	sm.RemoveGlobalData("table")
+	ssm.Info("third")
+	require.Len(t, l.entries, 3, "no entries added")
+	assert.Equal(t, map[string]interface{}{"version": 2}, l.entries[2].Globals)
+	assert.Equal(t, map[string]interface{}{"version": 2, "table": "users"}, e.Globals, "the globals of the previous entries should not change")
+
+	// appending to the tags of an entry should not change the cache
+	_ = append(l.entries[2].Tags, "[other]")
+	ssm.Info("fourth")
+	require.Len(t, l.entries, 4, "no entries added")
+	assert.Equal(t, []string{"[api]", "[db]", "[query]"}, l.entries[3].Tags)
+}
+
+func TestManagerCacheConcurrency(t *testing.T) {
+	t.Parallel()
+
+	m := NewManagerWithTag("[app]")
+	sm := m.NewSubManager("[db]")
+	l := &entrySliceLogger{}
+	require.NoError(t, sm.Add(l))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			m.AddGlobalData(fmt.Sprintf("key%d", i), i)
+			m.SetTag(fmt.Sprintf("[app%d]", i))
+		}(i)
+		go func() {
+			defer wg.Done()
+			sm.Info("message")
+		}()
+	}
+	wg.Wait()
+
+	// once all the writes are done, the cache must be up to date
+	sm.Info("last")
+	e := l.entries[len(l.entries)-1]
+	assert.Len(t, e.Globals, 10)
+	assert.Equal(t, m.Tag()+"[db]", e.Tag)
+}
+
+func BenchmarkManagerTree(b *testing.B) {
+	// newTree returns the deepest manager of a tree of the given depth,
+	// with a few globals per manager
+	newTree := func(depth int, l Logger) Manager {
+		m := NewManagerWithTag("[root]")
+		if err := m.Add(l); err != nil {
+			b.Fatal(err)
+		}
+		for i := 0; i < depth; i++ {
+			m.AddGlobalData(fmt.Sprintf("key%d", i), i)
+			m.AddGlobalData(fmt.Sprintf("name%d", i), "value")
+			m = m.NewSubManager(fmt.Sprintf("[level%d]", i))
+		}
+		return m
+	}
+
+	for _, depth := range []int{1, 5} {
+		b.Run(fmt.Sprintf("format path depth %d", depth), func(b *testing.B) {
+			l := &SliceLogger{}
+			m := newTree(depth, l)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.Info("message")
+				// we don't want to measure the growth of the slice
+				if i%1000 == 0 {
+					l.data = nil
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("entry path depth %d", depth), func(b *testing.B) {
+			m := newTree(depth, &nopEntryLogger{})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.Info("message")
+			}
+		})
+	}
+}
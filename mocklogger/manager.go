@@ -5,6 +5,7 @@
 package mocklogger
 
 import (
+	context "context"
 	go_logger "github.com/Nivl/go-logger"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -59,6 +60,58 @@ func (mr *MockManagerMockRecorder) AddGlobalData(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGlobalData", reflect.TypeOf((*MockManager)(nil).AddGlobalData), arg0, arg1)
 }
 
+// AddShared mocks base method
+func (m *MockManager) AddShared(arg0 go_logger.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddShared", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddShared indicates an expected call of AddShared
+func (mr *MockManagerMockRecorder) AddShared(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShared", reflect.TypeOf((*MockManager)(nil).AddShared), arg0)
+}
+
+// Children mocks base method
+func (m *MockManager) Children() []go_logger.Manager {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Children")
+	ret0, _ := ret[0].([]go_logger.Manager)
+	return ret0
+}
+
+// Children indicates an expected call of Children
+func (mr *MockManagerMockRecorder) Children() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Children", reflect.TypeOf((*MockManager)(nil).Children))
+}
+
+// ClearMessagePolicy mocks base method
+func (m *MockManager) ClearMessagePolicy() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearMessagePolicy")
+}
+
+// ClearMessagePolicy indicates an expected call of ClearMessagePolicy
+func (mr *MockManagerMockRecorder) ClearMessagePolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearMessagePolicy", reflect.TypeOf((*MockManager)(nil).ClearMessagePolicy))
+}
+
+// ClearMinLevel mocks base method
+func (m *MockManager) ClearMinLevel() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearMinLevel")
+}
+
+// ClearMinLevel indicates an expected call of ClearMinLevel
+func (mr *MockManagerMockRecorder) ClearMinLevel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearMinLevel", reflect.TypeOf((*MockManager)(nil).ClearMinLevel))
+}
+
 // Close mocks base method
 func (m *MockManager) Close() []error {
 	m.ctrl.T.Helper()
@@ -73,6 +126,34 @@ func (mr *MockManagerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockManager)(nil).Close))
 }
 
+// CloseContext mocks base method
+func (m *MockManager) CloseContext(arg0 context.Context) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseContext", arg0)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// CloseContext indicates an expected call of CloseContext
+func (mr *MockManagerMockRecorder) CloseContext(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseContext", reflect.TypeOf((*MockManager)(nil).CloseContext), arg0)
+}
+
+// Context mocks base method
+func (m *MockManager) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockManagerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockManager)(nil).Context))
+}
+
 // Debug mocks base method
 func (m *MockManager) Debug(arg0 ...interface{}) {
 	m.ctrl.T.Helper()
@@ -106,6 +187,32 @@ func (mr *MockManagerMockRecorder) Debugf(arg0 interface{}, arg1 ...interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debugf", reflect.TypeOf((*MockManager)(nil).Debugf), varargs...)
 }
 
+// Detach mocks base method
+func (m *MockManager) Detach(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Detach", arg0)
+}
+
+// Detach indicates an expected call of Detach
+func (mr *MockManagerMockRecorder) Detach(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detach", reflect.TypeOf((*MockManager)(nil).Detach), arg0)
+}
+
+// Dump mocks base method
+func (m *MockManager) Dump() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dump")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Dump indicates an expected call of Dump
+func (mr *MockManagerMockRecorder) Dump() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dump", reflect.TypeOf((*MockManager)(nil).Dump))
+}
+
 // Error mocks base method
 func (m *MockManager) Error(arg0 ...interface{}) {
 	m.ctrl.T.Helper()
@@ -139,6 +246,20 @@ func (mr *MockManagerMockRecorder) Errorf(arg0 interface{}, arg1 ...interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Errorf", reflect.TypeOf((*MockManager)(nil).Errorf), varargs...)
 }
 
+// Flush mocks base method
+func (m *MockManager) Flush(arg0 context.Context) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", arg0)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush
+func (mr *MockManagerMockRecorder) Flush(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockManager)(nil).Flush), arg0)
+}
+
 // FullTag mocks base method
 func (m *MockManager) FullTag() string {
 	m.ctrl.T.Helper()
@@ -153,6 +274,20 @@ func (mr *MockManagerMockRecorder) FullTag() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FullTag", reflect.TypeOf((*MockManager)(nil).FullTag))
 }
 
+// Globals mocks base method
+func (m *MockManager) Globals() map[string]interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Globals")
+	ret0, _ := ret[0].(map[string]interface{})
+	return ret0
+}
+
+// Globals indicates an expected call of Globals
+func (mr *MockManagerMockRecorder) Globals() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Globals", reflect.TypeOf((*MockManager)(nil).Globals))
+}
+
 // ID mocks base method
 func (m *MockManager) ID() string {
 	m.ctrl.T.Helper()
@@ -200,6 +335,20 @@ func (mr *MockManagerMockRecorder) Infof(arg0 interface{}, arg1 ...interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Infof", reflect.TypeOf((*MockManager)(nil).Infof), varargs...)
 }
 
+// IsClosed mocks base method
+func (m *MockManager) IsClosed() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsClosed")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsClosed indicates an expected call of IsClosed
+func (mr *MockManagerMockRecorder) IsClosed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsClosed", reflect.TypeOf((*MockManager)(nil).IsClosed))
+}
+
 // Log mocks base method
 func (m *MockManager) Log(arg0 ...interface{}) {
 	m.ctrl.T.Helper()
@@ -233,6 +382,34 @@ func (mr *MockManagerMockRecorder) Logf(arg0 interface{}, arg1 ...interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logf", reflect.TypeOf((*MockManager)(nil).Logf), varargs...)
 }
 
+// Loggers mocks base method
+func (m *MockManager) Loggers() []go_logger.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Loggers")
+	ret0, _ := ret[0].([]go_logger.Logger)
+	return ret0
+}
+
+// Loggers indicates an expected call of Loggers
+func (mr *MockManagerMockRecorder) Loggers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Loggers", reflect.TypeOf((*MockManager)(nil).Loggers))
+}
+
+// MinLevel mocks base method
+func (m *MockManager) MinLevel() go_logger.Level {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MinLevel")
+	ret0, _ := ret[0].(go_logger.Level)
+	return ret0
+}
+
+// MinLevel indicates an expected call of MinLevel
+func (mr *MockManagerMockRecorder) MinLevel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MinLevel", reflect.TypeOf((*MockManager)(nil).MinLevel))
+}
+
 // NewSubManager mocks base method
 func (m *MockManager) NewSubManager(arg0 string) go_logger.Manager {
 	m.ctrl.T.Helper()
@@ -247,6 +424,32 @@ func (mr *MockManagerMockRecorder) NewSubManager(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSubManager", reflect.TypeOf((*MockManager)(nil).NewSubManager), arg0)
 }
 
+// OnSinkError mocks base method
+func (m *MockManager) OnSinkError(arg0 go_logger.SinkErrorFunc) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnSinkError", arg0)
+}
+
+// OnSinkError indicates an expected call of OnSinkError
+func (mr *MockManagerMockRecorder) OnSinkError(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnSinkError", reflect.TypeOf((*MockManager)(nil).OnSinkError), arg0)
+}
+
+// Parent mocks base method
+func (m *MockManager) Parent() go_logger.Manager {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Parent")
+	ret0, _ := ret[0].(go_logger.Manager)
+	return ret0
+}
+
+// Parent indicates an expected call of Parent
+func (mr *MockManagerMockRecorder) Parent() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parent", reflect.TypeOf((*MockManager)(nil).Parent))
+}
+
 // Remove mocks base method
 func (m *MockManager) Remove(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -273,6 +476,54 @@ func (mr *MockManagerMockRecorder) RemoveGlobalData(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGlobalData", reflect.TypeOf((*MockManager)(nil).RemoveGlobalData), arg0)
 }
 
+// SetClock mocks base method
+func (m *MockManager) SetClock(arg0 go_logger.Clock) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetClock", arg0)
+}
+
+// SetClock indicates an expected call of SetClock
+func (mr *MockManagerMockRecorder) SetClock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetClock", reflect.TypeOf((*MockManager)(nil).SetClock), arg0)
+}
+
+// SetContext mocks base method
+func (m *MockManager) SetContext(arg0 context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetContext", arg0)
+}
+
+// SetContext indicates an expected call of SetContext
+func (mr *MockManagerMockRecorder) SetContext(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContext", reflect.TypeOf((*MockManager)(nil).SetContext), arg0)
+}
+
+// SetMessagePolicy mocks base method
+func (m *MockManager) SetMessagePolicy(arg0 go_logger.MessagePolicy) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMessagePolicy", arg0)
+}
+
+// SetMessagePolicy indicates an expected call of SetMessagePolicy
+func (mr *MockManagerMockRecorder) SetMessagePolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMessagePolicy", reflect.TypeOf((*MockManager)(nil).SetMessagePolicy), arg0)
+}
+
+// SetMinLevel mocks base method
+func (m *MockManager) SetMinLevel(arg0 go_logger.Level) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMinLevel", arg0)
+}
+
+// SetMinLevel indicates an expected call of SetMinLevel
+func (mr *MockManagerMockRecorder) SetMinLevel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMinLevel", reflect.TypeOf((*MockManager)(nil).SetMinLevel), arg0)
+}
+
 // SetTag mocks base method
 func (m *MockManager) SetTag(arg0 string) {
 	m.ctrl.T.Helper()
@@ -298,3 +549,15 @@ func (mr *MockManagerMockRecorder) Tag() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tag", reflect.TypeOf((*MockManager)(nil).Tag))
 }
+
+// Walk mocks base method
+func (m *MockManager) Walk(arg0 func(go_logger.Manager) bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Walk", arg0)
+}
+
+// Walk indicates an expected call of Walk
+func (mr *MockManagerMockRecorder) Walk(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Walk", reflect.TypeOf((*MockManager)(nil).Walk), arg0)
+}
@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MultilinePolicy represents the way the text formatters print messages
+// containing new lines
+type MultilinePolicy int
+
+// All the policies supported by the text formatters
+const (
+	// MultilineRaw prints the message as it is
+	MultilineRaw MultilinePolicy = iota
+	// MultilineEscape replaces the new lines by "\n", and the backslashes
+	// by "\\", so every entry stays on a single line
+	MultilineEscape
+	// MultilineIndent indents the continuation lines with
+	// MultilineIndentation, so they can be told apart from the next
+	// entries
+	MultilineIndent
+	// MultilineSplit prints every line as its own entry. The entries
+	// share the same MultilineIDKey field, and have their position in
+	// the MultilinePartKey field, starting at 1
+	MultilineSplit
+)
+
+// Keys and values used by the multi-line policies
+const (
+	// MultilineIndentation is the prefix of the continuation lines
+	// printed with MultilineIndent
+	MultilineIndentation = "    "
+	// MultilineIDKey is the field containing the ID shared by the entries
+	// of a message split with MultilineSplit
+	MultilineIDKey = "multiline_id"
+	// MultilinePartKey is the field containing the position of a line in
+	// a message split with MultilineSplit
+	MultilinePartKey = "multiline_part"
+	// TruncationMarker is appended to the messages truncated because of
+	// MaxMessageLength
+	TruncationMarker = "…[truncated]"
+)
+
+var (
+	// The backslashes are escaped too, so an escaped new line cannot be
+	// confused with a backslash followed by an "n" in the message
+	escapeNewLines = strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`)
+	indentNewLines = strings.NewReplacer("\r\n", "\n"+MultilineIndentation, "\n", "\n"+MultilineIndentation)
+)
+
+// MessagePolicy contains the options of the text formatters regarding
+// long and multi-line messages
+type MessagePolicy struct {
+	// Multiline is the way the messages containing new lines are printed
+	Multiline MultilinePolicy
+
+	// MaxMessageLength is the maximum size of a message, in bytes.
+	// Longer messages are truncated and end with TruncationMarker.
+	// There are no limits if 0
+	MaxMessageLength int
+}
+
+// formatMessage applies the policy to the message of an entry, and
+// calls format with the resulting entries. The outputs of all the
+// entries are concatenated
+func (p MessagePolicy) formatMessage(e *Entry, format func(e *Entry) ([]byte, error)) ([]byte, error) {
+	msg := p.truncate(e.Message)
+
+	switch p.Multiline {
+	case MultilineEscape:
+		msg = escapeNewLines.Replace(msg)
+	case MultilineIndent:
+		msg = indentNewLines.Replace(msg)
+	case MultilineSplit:
+		if strings.Contains(msg, "\n") {
+			return p.split(e, msg, format)
+		}
+	}
+
+	if msg == e.Message {
+		return format(e)
+	}
+	// The entry is shared with the other loggers, so we work on a copy
+	updated := *e
+	updated.Message = msg
+	return format(&updated)
+}
+
+// split formats every line of msg as its own entry
+func (p MessagePolicy) split(e *Entry, msg string, format func(e *Entry) ([]byte, error)) ([]byte, error) {
+	var buf bytes.Buffer
+	id := uuid.New().String()
+	for i, line := range strings.Split(strings.Replace(msg, "\r\n", "\n", -1), "\n") {
+		part := *e
+		part.Message = line
+		part.Fields = make(map[string]interface{}, len(e.Fields)+2)
+		for k, v := range e.Fields {
+			part.Fields[k] = v
+		}
+		part.Fields[MultilineIDKey] = id
+		part.Fields[MultilinePartKey] = i + 1
+
+		data, err := format(&part)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// truncate returns msg truncated to MaxMessageLength, without breaking
+// a UTF-8 character
+func (p MessagePolicy) truncate(msg string) string {
+	if p.MaxMessageLength <= 0 || len(msg) <= p.MaxMessageLength {
+		return msg
+	}
+
+	end := p.MaxMessageLength
+	for end > 0 && !utf8.RuneStart(msg[end]) {
+		end--
+	}
+	return msg[:end] + TruncationMarker
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessagePolicy(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2019, 5, 4, 10, 30, 0, 0, time.UTC)
+	newFormatter := func(p MessagePolicy) *TemplateFormatter {
+		f, err := NewTemplateFormatter("{level} {msg} {fields}")
+		require.NoError(t, err)
+		f.MessagePolicy = p
+		f.now = func() time.Time { return now }
+		return f
+	}
+	query := "SELECT *\r\nFROM users\nWHERE id = 1"
+
+	testCases := []struct {
+		description string
+		policy      MessagePolicy
+		message     string
+		expected    string
+	}{
+		{
+			description: "raw",
+			policy:      MessagePolicy{Multiline: MultilineRaw},
+			message:     query,
+			expected:    "ERROR SELECT *\r\nFROM users\nWHERE id = 1\n",
+		},
+		{
+			description: "escape",
+			policy:      MessagePolicy{Multiline: MultilineEscape},
+			message:     query,
+			expected:    `ERROR SELECT *\nFROM users\nWHERE id = 1` + "\n",
+		},
+		{
+			description: "escape backslashes",
+			policy:      MessagePolicy{Multiline: MultilineEscape},
+			message:     `C:\new` + "\nfolder",
+			expected:    `ERROR C:\\new\nfolder` + "\n",
+		},
+		{
+			description: "indent",
+			policy:      MessagePolicy{Multiline: MultilineIndent},
+			message:     query,
+			expected:    "ERROR SELECT *\n    FROM users\n    WHERE id = 1\n",
+		},
+		{
+			description: "split a single line",
+			policy:      MessagePolicy{Multiline: MultilineSplit},
+			message:     "SELECT 1",
+			expected:    "ERROR SELECT 1\n",
+		},
+		{
+			description: "truncate",
+			policy:      MessagePolicy{MaxMessageLength: 8},
+			message:     query,
+			expected:    "ERROR SELECT *" + TruncationMarker + "\n",
+		},
+		{
+			description: "truncate before a multi-byte character",
+			policy:      MessagePolicy{MaxMessageLength: 4},
+			message:     "abcé",
+			expected:    "ERROR abc" + TruncationMarker + "\n",
+		},
+		{
+			description: "messages short enough are not truncated",
+			policy:      MessagePolicy{MaxMessageLength: 8},
+			message:     "SELECT 1",
+			expected:    "ERROR SELECT 1\n",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Entry{Level: LevelError, Message: tc.message}
+			data, err := newFormatter(tc.policy).Format(e)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(data))
+			assert.Equal(t, tc.message, e.Message, "the entry should not be modified")
+		})
+	}
+
+	t.Run("split", func(t *testing.T) {
+		t.Parallel()
+
+		f := newFormatter(MessagePolicy{Multiline: MultilineSplit})
+		f.FieldsFormat = FieldsLogfmt
+		e := &Entry{
+			Level:   LevelError,
+			Message: query,
+			Fields:  map[string]interface{}{"db": "main"},
+		}
+		data, err := f.Format(e)
+		require.NoError(t, err)
+		assert.Len(t, e.Fields, 1, "the entry should not be modified")
+
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		require.Len(t, lines, 3)
+		id := strings.Fields(lines[0])[4]
+		require.True(t, strings.HasPrefix(id, MultilineIDKey+"="))
+		assert.Equal(t, "ERROR SELECT * db=main "+id+" multiline_part=1", lines[0])
+		assert.Equal(t, "ERROR FROM users db=main "+id+" multiline_part=2", lines[1])
+		assert.Equal(t, "ERROR WHERE id = 1 db=main "+id+" multiline_part=3", lines[2])
+	})
+
+	t.Run("default policies", func(t *testing.T) {
+		t.Parallel()
+
+		e := &Entry{Time: now, Level: LevelError, Message: "a\nb"}
+
+		console := NewConsoleFormatter(&strings.Builder{}).(*ConsoleFormatter)
+		console.TimeFormat = ""
+		data, err := console.Format(e)
+		require.NoError(t, err)
+		assert.Equal(t, "ERROR a\n    b\n", string(data))
+
+		tmpl, err := NewTemplateFormatter("{msg}")
+		require.NoError(t, err)
+		data, err = tmpl.Format(e)
+		require.NoError(t, err)
+		assert.Equal(t, `a\nb`+"\n", string(data))
+
+		data, err = NewLogfmtFormatter().Format(e)
+		require.NoError(t, err)
+		assert.Equal(t, `time=2019-05-04T10:30:00Z level=error msg="a\nb"`+"\n", string(data))
+	})
+}
+
+func TestManagerMessagePolicy(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager()
+	sm := m.NewSubManager("[db]")
+	l := NewSliceLogger().(*SliceLogger)
+	require.NoError(t, sm.Add(l))
+
+	sm.Info("SELECT *\nFROM users")
+	assert.Equal(t, []string{"[INFO][db] SELECT *\nFROM users\n"}, l.data, "messages should be printed as they are by default")
+
+	m.SetMessagePolicy(MessagePolicy{Multiline: MultilineEscape, MaxMessageLength: 19})
+	sm.Info("SELECT *\nFROM users")
+	sm.Info("SELECT *\nFROM users\nWHERE id = 1")
+	require.Len(t, l.data, 3)
+	assert.Equal(t, `[INFO][db] SELECT *\nFROM users`+"\n", l.data[1], "the policy of the parent should be used")
+	assert.Equal(t, `[INFO][db] SELECT *\nFROM users`+TruncationMarker+"\n", l.data[2])
+
+	// the lines are sent to the logger at once
+	sm.SetMessagePolicy(MessagePolicy{Multiline: MultilineSplit})
+	sm.Info("a\nb")
+	require.Len(t, l.data, 4)
+	lines := strings.Split(l.data[3], "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, "[INFO][db] a", lines[0])
+	assert.Contains(t, lines[1], `"multiline_part":1`)
+	assert.Equal(t, "[db] b", lines[2])
+	assert.Contains(t, lines[3], `"multiline_part":2`)
+
+	sm.ClearMessagePolicy()
+	m.ClearMessagePolicy()
+	sm.Info("a\nb")
+	assert.Equal(t, "[INFO][db] a\nb\n", l.data[4])
+}
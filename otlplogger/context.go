@@ -0,0 +1,32 @@
+package otlplogger
+
+import (
+	"context"
+)
+
+type spanContextKey struct{}
+
+type spanContext struct {
+	traceID string
+	spanID  string
+}
+
+// ContextWithSpan returns a copy of ctx containing the given trace and
+// span IDs, as hex strings.
+// This is only useful if Config.SpanContext is not set, which is the case
+// when the application doesn't use the OpenTelemetry SDK
+func ContextWithSpan(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, spanContext{
+		traceID: traceID,
+		spanID:  spanID,
+	})
+}
+
+// spanFromContext returns the trace and span IDs set with ContextWithSpan
+func spanFromContext(ctx context.Context) (traceID, spanID string) {
+	sc, ok := ctx.Value(spanContextKey{}).(spanContext)
+	if !ok {
+		return "", ""
+	}
+	return sc.traceID, sc.spanID
+}
@@ -0,0 +1,433 @@
+// Package otlplogger contains a logger that exports entries to an
+// OpenTelemetry collector using OTLP/HTTP with JSON encoding
+package otlplogger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/pkg/errors"
+)
+
+// DefaultEndpoint is the endpoint used when none is provided
+const DefaultEndpoint = "http://localhost:4318/v1/logs"
+
+// Default values of the configuration
+const (
+	// DefaultBatchSize is the batch size used when none is provided
+	DefaultBatchSize = 100
+	// DefaultQueueSize is the number of batches waiting to be sent
+	// used when none is provided
+	DefaultQueueSize = 10
+	// DefaultFlushInterval is the flush interval used when none is
+	// provided
+	DefaultFlushInterval = 5 * time.Second
+	// DefaultTimeout is the timeout of the requests used when no
+	// client is provided
+	DefaultTimeout = 10 * time.Second
+)
+
+// SequenceAttribute is the attribute containing the sequence number of
+// the entries, used to order the entries logged at the same time
+const SequenceAttribute = "event.sequence"
+
+// defaultScopeName is the name of the instrumentation scope used for the
+// entries that don't have any tag
+const defaultScopeName = "github.com/Nivl/go-logger"
+
+// List of all errors
+var (
+	ErrClosed    = errors.New("exporter is closed")
+	ErrQueueFull = errors.New("too many batches are waiting to be sent, the entries have been dropped")
+)
+
+// we make sure Exporter implements EntryLogger and Flusher
+var (
+	_ logger.EntryLogger = (*Exporter)(nil)
+	_ logger.Flusher     = (*Exporter)(nil)
+)
+
+// SpanContextFunc extracts the trace and span IDs, as hex strings, from
+// a context. Empty strings are returned if the context doesn't contain
+// any span
+type SpanContextFunc func(ctx context.Context) (traceID, spanID string)
+
+// Config contains the configuration of an Exporter
+type Config struct {
+	// Endpoint is the full URL of the collector's logs endpoint.
+	// Defaults to DefaultEndpoint
+	Endpoint string
+
+	// Headers contains extra HTTP headers sent with every request
+	Headers map[string]string
+
+	// Resource contains the attributes of the resource producing the
+	// logs, like "service.name"
+	Resource map[string]interface{}
+
+	// BatchSize is the number of records to buffer before sending them
+	// to the collector. Defaults to DefaultBatchSize
+	BatchSize int
+
+	// QueueSize is the maximum number of full batches waiting to be
+	// sent. The entries are dropped when the queue is full.
+	// Defaults to DefaultQueueSize
+	QueueSize int
+
+	// FlushInterval is the maximum time an entry is buffered before
+	// being sent, even if its batch is not full.
+	// Defaults to DefaultFlushInterval
+	FlushInterval time.Duration
+
+	// Client is the HTTP client used to send the requests.
+	// Defaults to a client with a timeout of DefaultTimeout
+	Client *http.Client
+
+	// OnError is called when a batch sent in the background could not
+	// be sent to the collector
+	OnError func(err error)
+
+	// SpanContext is used to extract the trace and span IDs from the
+	// context of the entries.
+	// Defaults to a function that reads the IDs set with ContextWithSpan
+	SpanContext SpanContextFunc
+}
+
+// Exporter is a buffered logger that sends batches of entries to an
+// OpenTelemetry collector, using the OTLP/HTTP JSON protocol.
+// Entries are mapped to the OpenTelemetry LogRecord data model.
+//
+// The batches are sent in the background, so a slow collector never
+// blocks the application. Full batches are queued, and dropped when the
+// queue is full. The exporter must be closed to stop its goroutine
+type Exporter struct {
+	// dropped is first so it's 64-bit aligned for the atomic operations
+	dropped uint64
+
+	cfg Config
+
+	mu      sync.Mutex
+	records []*record
+	closed  bool
+
+	queue   chan []*record
+	flushes chan chan error
+	done    chan struct{}
+}
+
+// NewExporter creates and returns a logger that exports entries to an
+// OpenTelemetry collector
+func NewExporter(cfg Config) logger.Logger {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultEndpoint
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if cfg.SpanContext == nil {
+		cfg.SpanContext = spanFromContext
+	}
+
+	l := &Exporter{
+		cfg:     cfg,
+		queue:   make(chan []*record, cfg.QueueSize),
+		flushes: make(chan chan error),
+		done:    make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// ID returns the logger's unique ID
+func (l *Exporter) ID() string {
+	return "otlp-exporter:" + l.cfg.Endpoint
+}
+
+// Close sends the remaining buffered entries to the collector, and
+// stops the background goroutine
+// the logger may not be reusable after being closed
+func (l *Exporter) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	err := l.Flush()
+	close(l.done)
+	return err
+}
+
+// IsClosed returns wether the logger is closed or not
+func (l *Exporter) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// Dropped returns the number of entries dropped because the queue was
+// full
+func (l *Exporter) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// Flush sends all the buffered and queued entries to the collector, and
+// waits for them to be sent
+func (l *Exporter) Flush() error {
+	res := make(chan error, 1)
+	select {
+	case l.flushes <- res:
+		return <-res
+	case <-l.done:
+		// everything has been sent when the exporter got closed
+		return nil
+	}
+}
+
+// run sends the batches to the collector until the exporter is closed
+func (l *Exporter) run() {
+	ticker := time.NewTicker(l.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case records := <-l.queue:
+			l.report(l.send(records))
+		case <-ticker.C:
+			l.report(l.sendAll())
+		case res := <-l.flushes:
+			res <- l.sendAll()
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// sendAll sends the queued batches, then the buffered entries
+func (l *Exporter) sendAll() error {
+	var errs []string
+	for queued := true; queued; {
+		select {
+		case records := <-l.queue:
+			if err := l.send(records); err != nil {
+				errs = append(errs, err.Error())
+			}
+		default:
+			queued = false
+		}
+	}
+
+	l.mu.Lock()
+	records := l.records
+	l.records = nil
+	l.mu.Unlock()
+	if err := l.send(records); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// report sends an error that cannot be returned to OnError
+func (l *Exporter) report(err error) {
+	if err != nil && l.cfg.OnError != nil {
+		l.cfg.OnError(err)
+	}
+}
+
+// LogEntry buffers an entry, and queues the buffer to be sent to the
+// collector once it's full.
+// Returns ErrQueueFull if the batch had to be dropped
+func (l *Exporter) LogEntry(e *logger.Entry) error {
+	r := l.newRecord(e)
+
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ErrClosed
+	}
+	l.records = append(l.records, r)
+	if len(l.records) < l.cfg.BatchSize {
+		l.mu.Unlock()
+		return nil
+	}
+	records := l.records
+	l.records = nil
+
+	// The batch is queued while holding the lock, so it cannot be queued
+	// once Close drained the queue. Sending never blocks
+	defer l.mu.Unlock()
+	select {
+	case l.queue <- records:
+		return nil
+	default:
+		atomic.AddUint64(&l.dropped, uint64(len(records)))
+		return ErrQueueFull
+	}
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (l *Exporter) Error(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelError)
+}
+
+// Info logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Println.
+func (l *Exporter) Info(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelInfo)
+}
+
+// Debug logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Println.
+func (l *Exporter) Debug(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelDebug)
+}
+
+// Log logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Println.
+func (l *Exporter) Log(msg string) {
+	logger.WriteLevel(l, msg, logger.LevelDefault)
+}
+
+// newRecord converts an entry to an OTLP LogRecord
+func (l *Exporter) newRecord(e *logger.Entry) *record {
+	observed := time.Now()
+	occurred := e.Time
+	if occurred.IsZero() {
+		occurred = observed
+	}
+
+	sevNumber, sevText := severity(e.Level)
+	r := &record{
+		scope: e.Tag,
+		LogRecord: logRecord{
+			TimeUnixNano:         strconv.FormatInt(occurred.UnixNano(), 10),
+			ObservedTimeUnixNano: strconv.FormatInt(observed.UnixNano(), 10),
+			SeverityNumber:       sevNumber,
+			SeverityText:         sevText,
+			Body:                 newValue(e.Message),
+			Attributes:           newKeyValues(e.Data()),
+		},
+	}
+	if e.Seq != 0 {
+		seq := strconv.FormatUint(e.Seq, 10)
+		r.LogRecord.Attributes = append(r.LogRecord.Attributes, keyValue{
+			Key:   SequenceAttribute,
+			Value: value{IntValue: &seq},
+		})
+	}
+	if e.Context != nil {
+		r.LogRecord.TraceID, r.LogRecord.SpanID = l.cfg.SpanContext(e.Context)
+	}
+	return r
+}
+
+// send groups the records by scope and sends them to the collector
+func (l *Exporter) send(records []*record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	req := &exportRequest{
+		ResourceLogs: []resourceLogs{
+			{
+				Resource: resource{
+					Attributes: newKeyValues(l.cfg.Resource),
+				},
+				ScopeLogs: groupByScope(records),
+			},
+		},
+	}
+
+	body, err := req.encode()
+	if err != nil {
+		return errors.Wrap(err, "could not encode the records")
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, l.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not create the request")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range l.cfg.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	res, err := l.cfg.Client.Do(httpReq)
+	if err != nil {
+		return errors.Wrap(err, "could not send the records")
+	}
+	defer res.Body.Close() //nolint:errcheck
+
+	// We drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, res.Body) //nolint:errcheck
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("collector returned unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// groupByScope groups the records by instrumentation scope, keeping the
+// order in which the scopes first appear
+func groupByScope(records []*record) []scopeLogs {
+	scopes := []scopeLogs{}
+	indexes := map[string]int{}
+	for _, r := range records {
+		name := r.scope
+		if name == "" {
+			name = defaultScopeName
+		}
+		i, ok := indexes[name]
+		if !ok {
+			i = len(scopes)
+			indexes[name] = i
+			scopes = append(scopes, scopeLogs{Scope: scope{Name: name}})
+		}
+		scopes[i].LogRecords = append(scopes[i].LogRecords, r.LogRecord)
+	}
+	return scopes
+}
+
+// severity returns the OpenTelemetry severity number and text of a level
+func severity(lvl logger.Level) (number int, text string) {
+	switch lvl {
+	case logger.LevelDebug:
+		return 5, lvl.String()
+	case logger.LevelInfo:
+		return 9, lvl.String()
+	case logger.LevelError:
+		return 17, lvl.String()
+	default:
+		// Log is used for messages that might result a failure, which
+		// is what WARN is for in OpenTelemetry
+		return 13, lvl.String()
+	}
+}
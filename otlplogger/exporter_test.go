@@ -0,0 +1,311 @@
+package otlplogger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	logger "github.com/Nivl/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collector is a stand-in for an OpenTelemetry collector that records
+// all the requests it receives
+type collector struct {
+	sync.Mutex
+	server   *httptest.Server
+	status   int
+	requests []*exportRequest
+	headers  []http.Header
+}
+
+func newCollector(t *testing.T) *collector {
+	c := &collector{status: http.StatusOK}
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &exportRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+
+		c.Lock()
+		c.requests = append(c.requests, req)
+		c.headers = append(c.headers, r.Header)
+		status := c.status
+		c.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	return c
+}
+
+// received returns the requests received so far
+func (c *collector) received() []*exportRequest {
+	c.Lock()
+	defer c.Unlock()
+	return c.requests
+}
+
+// waitFor waits for the collector to have received n requests
+func (c *collector) waitFor(t *testing.T, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for len(c.received()) < n {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the requests")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("entries are sent by batch", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCollector(t)
+		defer c.server.Close()
+
+		l := NewExporter(Config{
+			Endpoint:  c.server.URL,
+			BatchSize: 2,
+			Headers:   map[string]string{"X-Api-Key": "key"},
+			Resource:  map[string]interface{}{"service.name": "test"},
+		})
+
+		m := logger.NewManagerWithTag("[app]")
+		require.NoError(t, m.Add(l))
+		m.AddGlobalData("user", "id")
+		m.SetContext(ContextWithSpan(m.Context(), "trace", "span"))
+
+		m.Info("a", "b")
+		require.Len(t, c.received(), 0, "the batch should not have been sent")
+
+		m.Error("c")
+		c.waitFor(t, 1)
+		require.Len(t, c.received(), 1, "the batch should have been sent")
+		c.Lock()
+		assert.Equal(t, "key", c.headers[0].Get("X-Api-Key"))
+		assert.Equal(t, "application/json", c.headers[0].Get("Content-Type"))
+		c.Unlock()
+
+		req := c.received()[0]
+		require.Len(t, req.ResourceLogs, 1)
+		res := req.ResourceLogs[0]
+		require.Len(t, res.Resource.Attributes, 1)
+		assert.Equal(t, "service.name", res.Resource.Attributes[0].Key)
+		assert.Equal(t, "test", *res.Resource.Attributes[0].Value.StringValue)
+
+		require.Len(t, res.ScopeLogs, 1)
+		assert.Equal(t, "[app]", res.ScopeLogs[0].Scope.Name)
+
+		records := res.ScopeLogs[0].LogRecords
+		require.Len(t, records, 2)
+		assert.Equal(t, 9, records[0].SeverityNumber)
+		assert.Equal(t, "INFO", records[0].SeverityText)
+		assert.Equal(t, "a b", *records[0].Body.StringValue)
+		assert.Equal(t, "trace", records[0].TraceID)
+		assert.Equal(t, "span", records[0].SpanID)
+		assert.NotEmpty(t, records[0].TimeUnixNano)
+		require.Len(t, records[0].Attributes, 2)
+		assert.Equal(t, "user", records[0].Attributes[0].Key)
+		assert.Equal(t, "id", *records[0].Attributes[0].Value.StringValue)
+		assert.Equal(t, SequenceAttribute, records[0].Attributes[1].Key)
+		require.NotNil(t, records[0].Attributes[1].Value.IntValue)
+		assert.NotEqual(t, *records[0].Attributes[1].Value.IntValue, *records[1].Attributes[1].Value.IntValue, "the entries should have different sequence numbers")
+
+		assert.Equal(t, 17, records[1].SeverityNumber)
+		assert.Equal(t, "c", *records[1].Body.StringValue)
+	})
+
+	t.Run("Close sends the remaining entries", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCollector(t)
+		defer c.server.Close()
+
+		l := NewExporter(Config{Endpoint: c.server.URL})
+		l.Log("message")
+		l.Debug("message")
+		require.Len(t, c.received(), 0, "the batch should not have been sent")
+
+		require.NoError(t, l.Close())
+		assert.True(t, l.IsClosed())
+		require.Len(t, c.received(), 1, "the batch should have been sent")
+
+		scopes := c.received()[0].ResourceLogs[0].ScopeLogs
+		require.Len(t, scopes, 1)
+		assert.Equal(t, defaultScopeName, scopes[0].Scope.Name)
+		require.Len(t, scopes[0].LogRecords, 2)
+		assert.Equal(t, 13, scopes[0].LogRecords[0].SeverityNumber)
+		assert.Equal(t, 5, scopes[0].LogRecords[1].SeverityNumber)
+
+		err := l.(logger.EntryLogger).LogEntry(&logger.Entry{Message: "message"})
+		assert.Equal(t, ErrClosed, err)
+	})
+
+	t.Run("entries logged while closing are not lost", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCollector(t)
+		defer c.server.Close()
+
+		l := NewExporter(Config{Endpoint: c.server.URL, BatchSize: 1, QueueSize: 100}).(*Exporter)
+		var accepted int64
+		var started, wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			started.Add(1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				started.Done()
+				for {
+					err := l.LogEntry(&logger.Entry{Message: "message"})
+					if err == ErrClosed {
+						return
+					}
+					if err == nil {
+						atomic.AddInt64(&accepted, 1)
+					}
+				}
+			}()
+		}
+		started.Wait()
+		require.NoError(t, l.Close())
+		wg.Wait()
+
+		sent := 0
+		for _, req := range c.received() {
+			for _, scope := range req.ResourceLogs[0].ScopeLogs {
+				sent += len(scope.LogRecords)
+			}
+		}
+		assert.Equal(t, int(atomic.LoadInt64(&accepted)), sent, "all the accepted entries should have been sent")
+	})
+
+	t.Run("entries are grouped by tag", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCollector(t)
+		defer c.server.Close()
+
+		l := NewExporter(Config{Endpoint: c.server.URL})
+		m := logger.NewManagerWithTag("[app]")
+		require.NoError(t, m.Add(l))
+		sm := m.NewSubManager("[sub]")
+
+		m.Log("a")
+		sm.Log("b")
+		m.Log("c")
+		require.NoError(t, l.Close())
+
+		require.Len(t, c.received(), 1)
+		scopes := c.received()[0].ResourceLogs[0].ScopeLogs
+		require.Len(t, scopes, 2)
+		assert.Equal(t, "[app]", scopes[0].Scope.Name)
+		assert.Len(t, scopes[0].LogRecords, 2)
+		assert.Equal(t, "[app][sub]", scopes[1].Scope.Name)
+		assert.Len(t, scopes[1].LogRecords, 1)
+	})
+
+	t.Run("collector errors are returned", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCollector(t)
+		defer c.server.Close()
+		c.Lock()
+		c.status = http.StatusInternalServerError
+		c.Unlock()
+
+		var mu sync.Mutex
+		var reported []error
+		l := NewExporter(Config{Endpoint: c.server.URL, BatchSize: 2, OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, err)
+		}})
+		defer l.Close() //nolint:errcheck
+
+		require.NoError(t, l.(logger.EntryLogger).LogEntry(&logger.Entry{Message: "a"}))
+		require.NoError(t, l.(logger.EntryLogger).LogEntry(&logger.Entry{Message: "b"}))
+		c.waitFor(t, 1)
+		require.NoError(t, l.(logger.EntryLogger).LogEntry(&logger.Entry{Message: "c"}))
+		assert.Error(t, l.(logger.Flusher).Flush(), "Flush should return the error")
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Len(t, reported, 1, "the error of the background send should have been reported")
+	})
+
+	t.Run("entries are sent periodically", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCollector(t)
+		defer c.server.Close()
+
+		l := NewExporter(Config{Endpoint: c.server.URL, FlushInterval: 10 * time.Millisecond})
+		defer l.Close() //nolint:errcheck
+		l.Info("message")
+		c.waitFor(t, 1)
+	})
+
+	t.Run("a stalled collector doesn't block the application", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer stalled.Close()
+
+		l := NewExporter(Config{
+			Endpoint:  stalled.URL,
+			BatchSize: 1,
+			QueueSize: 1,
+			Client:    &http.Client{Timeout: 5 * time.Second},
+		}).(*Exporter)
+		defer l.Close() //nolint:errcheck
+		defer close(release)
+
+		done := make(chan struct{})
+		var errs []error
+		go func() {
+			defer close(done)
+			for i := 0; i < 10; i++ {
+				errs = append(errs, l.LogEntry(&logger.Entry{Message: "message"}))
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("LogEntry is blocked by the collector")
+		}
+
+		assert.Contains(t, errs, ErrQueueFull)
+		assert.True(t, l.Dropped() > 0, "some entries should have been dropped")
+	})
+}
+
+func TestNewValue(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	encode := func(v interface{}) string {
+		b, err := json.Marshal(newValue(v))
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	assert.Equal(t, `{"stringValue":"str"}`, encode("str"))
+	assert.Equal(t, `{"boolValue":true}`, encode(true))
+	assert.Equal(t, `{"intValue":"42"}`, encode(42))
+	assert.Equal(t, `{"doubleValue":4.2}`, encode(4.2))
+	assert.Equal(t, `{}`, encode(nil))
+	assert.Equal(t, `{"arrayValue":{"values":[{"intValue":"1"},{"stringValue":"a"}]}}`, encode([]interface{}{1, "a"}))
+	assert.Equal(t, `{"kvlistValue":{"values":[{"key":"name","value":{"stringValue":"John"}}]}}`, encode(user{Name: "John"}))
+}
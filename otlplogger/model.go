@@ -0,0 +1,163 @@
+package otlplogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// This file contains the JSON representation of the OTLP logs data
+// model. Only the fields used by the exporter are defined.
+// https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/logs/v1/logs.proto
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+func (r *exportRequest) encode() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes,omitempty"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type logRecord struct {
+	// 64 bits integers are encoded as strings in OTLP/JSON
+	TimeUnixNano         string     `json:"timeUnixNano,omitempty"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano,omitempty"`
+	SeverityNumber       int        `json:"severityNumber"`
+	SeverityText         string     `json:"severityText,omitempty"`
+	Body                 value      `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+	TraceID              string     `json:"traceId,omitempty"`
+	SpanID               string     `json:"spanId,omitempty"`
+}
+
+// record is a LogRecord waiting to be sent
+type record struct {
+	scope     string
+	LogRecord logRecord
+}
+
+type keyValue struct {
+	Key   string `json:"key"`
+	Value value  `json:"value"`
+}
+
+type value struct {
+	StringValue *string      `json:"stringValue,omitempty"`
+	BoolValue   *bool        `json:"boolValue,omitempty"`
+	IntValue    *string      `json:"intValue,omitempty"`
+	DoubleValue *float64     `json:"doubleValue,omitempty"`
+	BytesValue  []byte       `json:"bytesValue,omitempty"`
+	ArrayValue  *arrayValue  `json:"arrayValue,omitempty"`
+	KvlistValue *kvlistValue `json:"kvlistValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []value `json:"values"`
+}
+
+type kvlistValue struct {
+	Values []keyValue `json:"values"`
+}
+
+// newKeyValues converts a map to a list of attributes, sorted by key
+func newKeyValues(data map[string]interface{}) []keyValue {
+	if len(data) == 0 {
+		return nil
+	}
+
+	kvs := make([]keyValue, 0, len(data))
+	for k, v := range data {
+		kvs = append(kvs, keyValue{Key: k, Value: newValue(v)})
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+	return kvs
+}
+
+// newValue converts any Go value to an OTLP AnyValue
+func newValue(v interface{}) value {
+	switch v := v.(type) {
+	case nil:
+		return value{}
+	case string:
+		return stringValue(v)
+	case bool:
+		return value{BoolValue: &v}
+	case int:
+		return intValue(int64(v))
+	case int8:
+		return intValue(int64(v))
+	case int16:
+		return intValue(int64(v))
+	case int32:
+		return intValue(int64(v))
+	case int64:
+		return intValue(v)
+	case uint8:
+		return intValue(int64(v))
+	case uint16:
+		return intValue(int64(v))
+	case uint32:
+		return intValue(int64(v))
+	case float32:
+		f := float64(v)
+		return value{DoubleValue: &f}
+	case float64:
+		return value{DoubleValue: &v}
+	case []byte:
+		return value{BytesValue: v}
+	case error:
+		return stringValue(v.Error())
+	case fmt.Stringer:
+		return stringValue(v.String())
+	case []interface{}:
+		values := make([]value, len(v))
+		for i, item := range v {
+			values[i] = newValue(item)
+		}
+		return value{ArrayValue: &arrayValue{Values: values}}
+	case map[string]interface{}:
+		return value{KvlistValue: &kvlistValue{Values: newKeyValues(v)}}
+	}
+
+	// For everything else (uint64, structs, typed slices and maps, etc.)
+	// we rely on the JSON representation of the value
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return stringValue(fmt.Sprintf("%+v", v))
+	}
+	var generic interface{}
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return stringValue(string(encoded))
+	}
+	return newValue(generic)
+}
+
+func stringValue(s string) value {
+	return value{StringValue: &s}
+}
+
+func intValue(i int64) value {
+	s := strconv.FormatInt(i, 10)
+	return value{IntValue: &s}
+}
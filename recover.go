@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// RecoverOption is an option of Recover and Go
+type RecoverOption func(*recoverConfig)
+
+// recoverConfig contains the configuration of Recover
+type recoverConfig struct {
+	repanic      bool
+	flushTimeout time.Duration
+}
+
+// Repanic makes Recover panic again once the panic has been logged
+func Repanic() RecoverOption {
+	return func(cfg *recoverConfig) {
+		cfg.repanic = true
+	}
+}
+
+// FlushTimeout sets how long Recover waits for the loggers to be
+// flushed. Defaults to SignalFlushTimeout
+func FlushTimeout(d time.Duration) RecoverOption {
+	return func(cfg *recoverConfig) {
+		cfg.flushTimeout = d
+	}
+}
+
+// Recover recovers from a panic, logs it as an error with its stack
+// trace using m, and flushes the loggers of the tree of m.
+// It must be called using defer:
+//
+//	defer logger.Recover(m)
+func Recover(m Manager, opts ...RecoverOption) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+
+	cfg := &recoverConfig{
+		flushTimeout: SignalFlushTimeout,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	logPanic(m, rec, debug.Stack())
+
+	// The entries of m are also sent to the loggers of its parents, so
+	// we flush the whole tree
+	root := m
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.flushTimeout)
+	defer cancel()
+	root.Flush(ctx)
+
+	if cfg.repanic {
+		panic(rec)
+	}
+}
+
+// Go runs fn in a new goroutine, and uses Recover to log its panics
+func Go(m Manager, fn func(), opts ...RecoverOption) {
+	go func() {
+		defer Recover(m, opts...)
+		fn()
+	}()
+}
+
+// logPanic logs a recovered panic with its stack trace
+func logPanic(m Manager, rec interface{}, stack []byte) {
+	args := []interface{}{
+		fmt.Sprintf("panic: %v", rec),
+		String("stack", string(stack)),
+	}
+	if err, ok := rec.(error); ok {
+		args = append(args, ErrorField(err))
+	}
+	m.Error(args...)
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panics are logged and the loggers flushed", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManagerWithTag("[app]")
+		m.AddGlobalData("version", 2)
+		l := &entrySliceLogger{}
+		flusher := newFlushSliceLogger("flusher")
+		require.NoError(t, m.Add(l))
+		require.NoError(t, m.Add(flusher))
+		sm := m.NewSubManager("[worker]")
+
+		assert.NotPanics(t, func() {
+			defer Recover(sm)
+			panic("boom")
+		})
+
+		require.Len(t, l.entries, 1)
+		e := l.entries[0]
+		assert.Equal(t, LevelError, e.Level)
+		assert.Equal(t, "panic: boom", e.Message)
+		assert.Equal(t, "[app][worker]", e.Tag, "the tags of the submanager should be used")
+		assert.Equal(t, map[string]interface{}{"version": 2}, e.Globals)
+		assert.Contains(t, e.Fields["stack"], "recover_test.go")
+		assert.Len(t, flusher.flushed, 1, "the loggers of the root should have been flushed")
+	})
+
+	t.Run("errors are added as field", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		l := &entrySliceLogger{}
+		require.NoError(t, m.Add(l))
+		err := errors.New("not found")
+
+		func() {
+			defer Recover(m)
+			panic(err)
+		}()
+		require.Len(t, l.entries, 1)
+		assert.Equal(t, err, l.entries[0].Fields[ErrorKey])
+	})
+
+	t.Run("nothing is logged without panic", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		l := &entrySliceLogger{}
+		require.NoError(t, m.Add(l))
+
+		func() {
+			defer Recover(m)
+		}()
+		assert.Empty(t, l.entries)
+	})
+
+	t.Run("Repanic panics again", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		l := &entrySliceLogger{}
+		require.NoError(t, m.Add(l))
+
+		assert.Panics(t, func() {
+			defer Recover(m, Repanic(), FlushTimeout(time.Second))
+			panic("boom")
+		})
+		assert.Len(t, l.entries, 1, "the panic should have been logged")
+	})
+}
+
+func TestGo(t *testing.T) {
+	t.Parallel()
+
+	m := NewManagerWithTag("[app]")
+	entries := make(chan *Entry, 1)
+	require.NoError(t, m.Add(&funcLogger{fn: func(e *Entry) {
+		entries <- e
+	}}))
+
+	Go(m.NewSubManager("[job]"), func() {
+		panic("boom")
+	})
+
+	select {
+	case e := <-entries:
+		assert.Equal(t, "[app][job]", e.Tag)
+		assert.Equal(t, "panic: boom", e.Message)
+	case <-time.After(time.Second):
+		t.Fatal("the panic has not been logged")
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// SharedErr is returned by Remove when a logger has been removed from a
+// manager, but has not been closed because other managers still own it
+type SharedErr struct {
+	Logger Logger
+
+	// Managers contains the managers that still use the logger, sorted
+	// by ID
+	Managers []Manager
+}
+
+func (e *SharedErr) Error() string {
+	return fmt.Sprintf("logger %s is still used by %d other managers", e.Logger.ID(), len(e.Managers))
+}
+
+// loggerRefs contains, for each logger, the managers it's attached to.
+// A manager owns the loggers added with Add, but not the ones added
+// with AddShared.
+// Loggers that cannot be used as map keys are not tracked, and are
+// closed by the manager that owns them
+var loggerRefs = struct {
+	sync.Mutex
+	managers map[Logger]map[*DefaultManager]bool
+}{
+	managers: map[Logger]map[*DefaultManager]bool{},
+}
+
+// trackable returns whether a logger can be used as a map key
+func trackable(l Logger) bool {
+	return reflect.TypeOf(l).Comparable()
+}
+
+// acquireLogger registers that a logger has been attached to a manager
+func acquireLogger(l Logger, m *DefaultManager, owner bool) {
+	if !trackable(l) {
+		return
+	}
+
+	loggerRefs.Lock()
+	defer loggerRefs.Unlock()
+
+	managers, ok := loggerRefs.managers[l]
+	if !ok {
+		managers = map[*DefaultManager]bool{}
+		loggerRefs.managers[l] = managers
+	}
+	managers[m] = owner
+}
+
+// releaseLogger registers that a logger has been removed from a manager.
+// Returns whether the logger should be closed, which is when the manager
+// was its last owner, and the other owners of the logger
+func releaseLogger(l Logger, m *DefaultManager, owner bool) (shouldClose bool, others []Manager) {
+	if !trackable(l) {
+		return owner, nil
+	}
+
+	loggerRefs.Lock()
+	defer loggerRefs.Unlock()
+
+	managers := loggerRefs.managers[l]
+	delete(managers, m)
+	if len(managers) == 0 {
+		delete(loggerRefs.managers, l)
+	}
+	if !owner {
+		return false, nil
+	}
+
+	for om, isOwner := range managers {
+		if isOwner {
+			others = append(others, om)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].ID() < others[j].ID()
+	})
+	return len(others) == 0, others
+}
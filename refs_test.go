@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedLoggers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a logger is closed by its last owner", func(t *testing.T) {
+		t.Parallel()
+
+		m1 := NewManager()
+		m2 := NewManager()
+		m3 := NewManager()
+		l := &SliceLogger{}
+		require.NoError(t, m1.Add(l))
+		require.NoError(t, m2.Add(l))
+		require.NoError(t, m3.Add(l))
+
+		err := m1.Remove(l.ID())
+		require.Error(t, err)
+		sharedErr, ok := err.(*SharedErr)
+		require.True(t, ok, "expected a *SharedErr")
+		assert.Equal(t, l, sharedErr.Logger)
+		expected := []Manager{m2, m3}
+		if m3.ID() < m2.ID() {
+			expected = []Manager{m3, m2}
+		}
+		assert.Equal(t, expected, sharedErr.Managers)
+		assert.False(t, l.IsClosed())
+
+		require.Empty(t, m2.Close())
+		assert.False(t, l.IsClosed(), "m3 still owns the logger")
+		m3.Info("message")
+		assert.Len(t, l.data, 1, "the logger should still be usable")
+
+		require.NoError(t, m3.Remove(l.ID()))
+		assert.True(t, l.IsClosed(), "the last owner should close the logger")
+	})
+
+	t.Run("shared loggers are never closed", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		sm := m.NewSubManager("[sub]")
+		l := &SliceLogger{}
+		l2 := &SliceLogger{id: "other"}
+		require.NoError(t, m.AddShared(l))
+		require.NoError(t, sm.AddShared(l2))
+		assert.Equal(t, ErrAlreadyExist, m.AddShared(l))
+
+		require.NoError(t, m.Remove(l.ID()))
+		assert.False(t, l.IsClosed())
+		assert.Empty(t, m.Loggers())
+
+		require.Empty(t, m.Close())
+		assert.False(t, l2.IsClosed())
+	})
+
+	t.Run("shared loggers don't prevent the owner from closing", func(t *testing.T) {
+		t.Parallel()
+
+		owner := NewManager()
+		user := NewManager()
+		l := &SliceLogger{}
+		require.NoError(t, owner.Add(l))
+		require.NoError(t, user.AddShared(l))
+
+		require.NoError(t, owner.Remove(l.ID()))
+		assert.True(t, l.IsClosed())
+
+		user.Info("message")
+		assert.Empty(t, user.Loggers(), "the closed logger should have been detached")
+	})
+
+	t.Run("Detach doesn't close the logger", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewManager()
+		l := &SliceLogger{}
+		require.NoError(t, m.Add(l))
+
+		m.Detach(l.ID())
+		m.Detach("unknown")
+		assert.Empty(t, m.Loggers())
+		assert.False(t, l.IsClosed())
+
+		// the logger is not referenced anymore, so a new owner closes it
+		m2 := NewManager()
+		require.NoError(t, m2.Add(l))
+		require.NoError(t, m2.Remove(l.ID()))
+		assert.True(t, l.IsClosed())
+	})
+
+	t.Run("untrackable loggers are closed by their owner", func(t *testing.T) {
+		t.Parallel()
+
+		m1 := NewManager()
+		m2 := NewManager()
+		l := uncomparableLogger{SliceLogger: &SliceLogger{}}
+		require.NoError(t, m1.Add(l))
+		require.NoError(t, m2.Add(l))
+
+		require.NoError(t, m1.Remove(l.ID()))
+		assert.True(t, l.IsClosed())
+	})
+}
+
+// uncomparableLogger is a logger that cannot be used as a map key
+type uncomparableLogger struct {
+	*SliceLogger
+	_ []string
+}
@@ -0,0 +1,247 @@
+package logger
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// we make sure RingLogger implements EntryLogger and http.Handler
+var (
+	_ EntryLogger  = (*RingLogger)(nil)
+	_ http.Handler = (*RingLogger)(nil)
+)
+
+// DefaultRingSize is the number of entries kept by a RingLogger when
+// no size is provided
+const DefaultRingSize = 100
+
+// NewRingLogger creates and returns a logger that keeps the last size
+// entries in memory, and sends them to target when an error is logged
+func NewRingLogger(size int, target Logger) *RingLogger {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+	return &RingLogger{
+		Trigger: LevelError,
+		id:      "ring-logger:" + uuid.New().String(),
+		target:  target,
+		entries: make([]*Entry, size),
+	}
+}
+
+// RingLogger is a go-routine safe "flight recorder" that keeps the last
+// entries of all levels in memory. When an entry of the Trigger level,
+// or above, is logged, the buffered entries are sent to the target
+// logger, followed by the entry itself, and the buffer is emptied.
+//
+// The buffer can also be sent on demand using Dump, or with an HTTP
+// request: GET returns the content of the buffer, and POST sends it to
+// the target logger.
+//
+// Targets that don't implement EntryLogger receive the messages prefixed
+// by the time of their entry, since they would otherwise use the time of
+// the dump.
+//
+// Closing a RingLogger doesn't close its target, which is usually
+// attached to a manager as well
+type RingLogger struct {
+	// Trigger is the level from which the entries trigger a dump.
+	// Defaults to LevelError. Must be set before the logger is used
+	Trigger Level
+
+	id     string
+	target Logger
+
+	mu      sync.Mutex
+	entries []*Entry
+	start   int // index of the oldest entry
+	count   int
+	closed  bool
+}
+
+// ID returns the logger's unique ID
+func (l *RingLogger) ID() string {
+	return l.id
+}
+
+// Close frees the buffered entries
+// the logger may not be reusable after being closed
+func (l *RingLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	l.reset()
+	return nil
+}
+
+// IsClosed returns wether the logger is closed or not
+func (l *RingLogger) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// LogEntry buffers the entry, and sends the buffer to the target if the
+// entry has the trigger level
+func (l *RingLogger) LogEntry(e *Entry) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return errors.New("logger is closed")
+	}
+
+	l.push(e)
+	if !e.Level.AtLeast(l.Trigger) {
+		l.mu.Unlock()
+		return nil
+	}
+	entries := l.take()
+	l.mu.Unlock()
+
+	return l.send(entries)
+}
+
+// Dump sends the buffered entries to the target logger, and empties the
+// buffer
+func (l *RingLogger) Dump() error {
+	l.mu.Lock()
+	entries := l.take()
+	l.mu.Unlock()
+
+	return l.send(entries)
+}
+
+// Entries returns the buffered entries, from the oldest to the newest
+func (l *RingLogger) Entries() []*Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.list()
+}
+
+// ServeHTTP implements http.Handler
+func (l *RingLogger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var buf strings.Builder
+		for _, e := range l.Entries() {
+			buf.WriteString(e.Time.Format(time.RFC3339Nano))
+			buf.WriteString(" ")
+			buf.WriteString(e.Level.String())
+			buf.WriteString(" ")
+			buf.WriteString(entryText(e))
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		// Nothing can be done if the response can't be written
+		_, _ = w.Write([]byte(buf.String())) //nolint:errcheck
+	case http.MethodPost:
+		if err := l.Dump(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// push adds an entry to the buffer, replacing the oldest one if the
+// buffer is full.
+// Must be called while holding the lock
+func (l *RingLogger) push(e *Entry) {
+	i := (l.start + l.count) % len(l.entries)
+	l.entries[i] = e
+	if l.count < len(l.entries) {
+		l.count++
+		return
+	}
+	l.start = (l.start + 1) % len(l.entries)
+}
+
+// list returns the buffered entries, from the oldest to the newest.
+// Must be called while holding the lock
+func (l *RingLogger) list() []*Entry {
+	entries := make([]*Entry, 0, l.count)
+	for i := 0; i < l.count; i++ {
+		entries = append(entries, l.entries[(l.start+i)%len(l.entries)])
+	}
+	return entries
+}
+
+// take returns the buffered entries and empties the buffer.
+// Must be called while holding the lock
+func (l *RingLogger) take() []*Entry {
+	entries := l.list()
+	l.reset()
+	return entries
+}
+
+// reset empties the buffer.
+// Must be called while holding the lock
+func (l *RingLogger) reset() {
+	for i := range l.entries {
+		l.entries[i] = nil
+	}
+	l.start = 0
+	l.count = 0
+}
+
+// send sends the entries to the target logger
+func (l *RingLogger) send(entries []*Entry) error {
+	_, structured := l.target.(EntryLogger)
+
+	var errs []string
+	for _, e := range entries {
+		msg := textFunc(e)
+		if !structured && !e.Time.IsZero() {
+			// The target only gets the message, and would use the time of
+			// the dump instead of the time of the entry
+			msg = timedTextFunc(e)
+		}
+		if err := writeEntry(l.target, e, msg); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return combineErrors("could not send the entries to "+l.target.ID(), errs)
+}
+
+// timedTextFunc returns a function that formats an entry prefixed by
+// its time
+func timedTextFunc(e *Entry) func() string {
+	return func() string {
+		return e.Time.Format(time.RFC3339Nano) + " " + entryText(e)
+	}
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (l *RingLogger) Error(msg string) {
+	WriteLevel(l, msg, LevelError)
+}
+
+// Info logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Println.
+func (l *RingLogger) Info(msg string) {
+	WriteLevel(l, msg, LevelInfo)
+}
+
+// Debug logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Println.
+func (l *RingLogger) Debug(msg string) {
+	WriteLevel(l, msg, LevelDebug)
+}
+
+// Log logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Println.
+func (l *RingLogger) Log(msg string) {
+	WriteLevel(l, msg, LevelDefault)
+}
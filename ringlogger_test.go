@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func messages(entries []*Entry) []string {
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.Message
+	}
+	return msgs
+}
+
+func TestRingLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the history is sent on error", func(t *testing.T) {
+		t.Parallel()
+
+		target := &entrySliceLogger{}
+		ring := NewRingLogger(3, target)
+		m := NewManager()
+		require.NoError(t, m.Add(ring))
+
+		m.Debug("1")
+		m.Info("2")
+		m.Debug("3")
+		m.Log("4")
+		assert.Empty(t, target.entries, "nothing should be sent before an error")
+		assert.Equal(t, []string{"2", "3", "4"}, messages(ring.Entries()), "the oldest entry should have been dropped")
+
+		m.Error("5")
+		assert.Equal(t, []string{"3", "4", "5"}, messages(target.entries))
+		assert.Empty(t, ring.Entries(), "the buffer should have been emptied")
+
+		m.Debug("6")
+		m.Error("7")
+		assert.Equal(t, []string{"3", "4", "5", "6", "7"}, messages(target.entries))
+	})
+
+	t.Run("the trigger level can be changed", func(t *testing.T) {
+		t.Parallel()
+
+		target := &entrySliceLogger{}
+		ring := NewRingLogger(0, target)
+		ring.Trigger = LevelInfo
+		ring.Debug("1")
+		ring.Info("2")
+		assert.Equal(t, []string{"1", "2"}, messages(target.entries))
+		assert.Len(t, ring.entries, DefaultRingSize)
+	})
+
+	t.Run("Dump sends the history on demand", func(t *testing.T) {
+		t.Parallel()
+
+		target := &SliceLogger{}
+		ring := NewRingLogger(10, target)
+		require.NoError(t, ring.LogEntry(&Entry{Level: LevelDebug, Message: "1", Tag: "[app]"}))
+
+		require.NoError(t, ring.Dump())
+		assert.Equal(t, []string{"[DEBUG][app] 1\n"}, target.data)
+		assert.Empty(t, ring.Entries())
+	})
+
+	t.Run("the original time is sent to string loggers", func(t *testing.T) {
+		t.Parallel()
+
+		target := &SliceLogger{}
+		ring := NewRingLogger(10, target)
+		at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		require.NoError(t, ring.LogEntry(&Entry{Time: at, Level: LevelDebug, Message: "1"}))
+		require.NoError(t, ring.LogEntry(&Entry{Time: at.Add(time.Second), Level: LevelError, Message: "2", Tag: "[app]"}))
+
+		expected := []string{
+			"[DEBUG]2020-01-02T03:04:05Z 1\n",
+			"[ERROR]2020-01-02T03:04:06Z [app] 2\n",
+		}
+		assert.Equal(t, expected, target.data)
+	})
+
+	t.Run("HTTP handler", func(t *testing.T) {
+		t.Parallel()
+
+		target := &entrySliceLogger{}
+		ring := NewRingLogger(10, target)
+		at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		require.NoError(t, ring.LogEntry(&Entry{Time: at, Level: LevelDebug, Message: "1"}))
+		require.NoError(t, ring.LogEntry(&Entry{Time: at, Level: LevelInfo, Message: "2", Tag: "[app]"}))
+
+		rec := httptest.NewRecorder()
+		ring.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		expected := "2020-01-02T03:04:05Z DEBUG 1\n2020-01-02T03:04:05Z INFO [app] 2\n"
+		assert.Equal(t, expected, rec.Body.String())
+		assert.Empty(t, target.entries, "GET should not send the entries")
+
+		rec = httptest.NewRecorder()
+		ring.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, []string{"1", "2"}, messages(target.entries))
+
+		rec = httptest.NewRecorder()
+		ring.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("Close doesn't close the target", func(t *testing.T) {
+		t.Parallel()
+
+		target := &SliceLogger{}
+		ring := NewRingLogger(10, target)
+		ring.Info("1")
+		require.NoError(t, ring.Close())
+		assert.True(t, ring.IsClosed())
+		assert.False(t, target.IsClosed())
+		assert.Empty(t, ring.Entries())
+		assert.Error(t, ring.LogEntry(&Entry{Level: LevelError}))
+	})
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+)
+
+// we make sure sinkErrorManager implements Manager
+var _ Manager = (*sinkErrorManager)(nil)
+
+// sinkErrorManager is the manager given to a SinkErrorFunc through the
+// context of the entry. The write errors of the entries it logs are not
+// reported, so a failing logger doesn't make the function call itself
+// endlessly
+type sinkErrorManager struct {
+	*DefaultManager
+}
+
+// reportSinkError calls fn with a write error of l. The entry given to fn
+// is a copy whose context contains a manager that can be used to log
+// the error
+func (m *DefaultManager) reportSinkError(fn SinkErrorFunc, l Logger, e *Entry, err error) {
+	ctx := e.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// The entry is shared with the other loggers, so we work on a copy
+	reported := *e
+	reported.Context = NewContext(ctx, &sinkErrorManager{DefaultManager: m})
+	fn(l, &reported, err)
+}
+
+// Errorf logs an error message
+// Arguments are handled in the manner of fmt.Printf
+func (m *sinkErrorManager) Errorf(msg string, args ...interface{}) {
+	m.logf(LevelError, msg, args, true)
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (m *sinkErrorManager) Error(args ...interface{}) {
+	m.log(LevelError, args, true)
+}
+
+// Infof logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Printf
+func (m *sinkErrorManager) Infof(msg string, args ...interface{}) {
+	m.logf(LevelInfo, msg, args, true)
+}
+
+// Info logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Println.
+func (m *sinkErrorManager) Info(args ...interface{}) {
+	m.log(LevelInfo, args, true)
+}
+
+// Debugf logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Printf
+func (m *sinkErrorManager) Debugf(msg string, args ...interface{}) {
+	m.logf(LevelDebug, msg, args, true)
+}
+
+// Debug logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Println.
+func (m *sinkErrorManager) Debug(args ...interface{}) {
+	m.log(LevelDebug, args, true)
+}
+
+// Logf logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Printf
+func (m *sinkErrorManager) Logf(msg string, args ...interface{}) {
+	m.logf(LevelDefault, msg, args, true)
+}
+
+// Log logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Println.
+func (m *sinkErrorManager) Log(args ...interface{}) {
+	m.log(LevelDefault, args, true)
+}
@@ -1,5 +1,7 @@
 package logger
 
+import "sync"
+
 // we make sure SliceLogger implements Logger
 var _ Logger = (*SliceLogger)(nil)
 
@@ -8,9 +10,10 @@ func NewSliceLogger() Logger {
 	return &SliceLogger{}
 }
 
-// SliceLogger is a logger that puts everything in a slice (useful for testing)
-// /!\ Not go-routine-safe
+// SliceLogger is a go-routine safe logger that puts everything in a
+// slice (useful for testing)
 type SliceLogger struct {
+	mu     sync.Mutex
 	data   []string
 	closed bool
 	id     string
@@ -24,36 +27,44 @@ func (l *SliceLogger) ID() string {
 }
 
 func (l *SliceLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.data = []string{}
 	l.closed = true
 	return nil
 }
 
 func (l *SliceLogger) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.data = []string{}
 }
 
 func (l *SliceLogger) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	return l.closed
 }
 
 func (l *SliceLogger) Error(msg string) {
-	l.write(msg, levelError)
+	l.write(msg, LevelError)
 }
 
 func (l *SliceLogger) Info(msg string) {
-	l.write(msg, levelInfo)
+	l.write(msg, LevelInfo)
 }
 
 func (l *SliceLogger) Debug(msg string) {
-	l.write(msg, levelDebug)
+	l.write(msg, LevelDebug)
 }
 
 func (l *SliceLogger) Log(msg string) {
-	l.write(msg, levelDefault)
+	l.write(msg, LevelDefault)
 }
 
-func (l *SliceLogger) write(msg string, lvl logLevel) {
+func (l *SliceLogger) write(msg string, lvl Level) {
 	msg = lvl.Tag() + msg
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.data = append(l.data, msg)
 }
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"log"
+	"sync/atomic"
 )
 
 // we make sure StderrLogger implements Logger
@@ -12,9 +13,10 @@ func NewStderrLogger() Logger {
 	return &StderrLogger{}
 }
 
-// StderrLogger is a non-buffered logger that writes on stderr
+// StderrLogger is a go-routine safe, non-buffered logger that writes on
+// stderr
 type StderrLogger struct {
-	closed bool
+	closed int32
 }
 
 // ID returns the logger's unique ID
@@ -25,26 +27,26 @@ func (l *StderrLogger) ID() string {
 // Close frees any resource allocated by the logger
 // the logger may not be reusable after being closed
 func (l *StderrLogger) Close() error {
-	l.closed = true
+	atomic.StoreInt32(&l.closed, 1)
 	return nil
 }
 
 // IsClosed returns wether the logger is closed or not
 func (l *StderrLogger) IsClosed() bool {
-	return l.closed
+	return atomic.LoadInt32(&l.closed) == 1
 }
 
 // Error logs an error message
 // Arguments are handled in the manner of fmt.Println.
 func (l *StderrLogger) Error(msg string) {
-	l.write(msg, levelError)
+	l.write(msg, LevelError)
 }
 
 // Info logs a message that may be helpful, but isn’t essential,
 // for troubleshooting
 // Arguments are handled in the manner of fmt.Println.
 func (l *StderrLogger) Info(msg string) {
-	l.write(msg, levelInfo)
+	l.write(msg, LevelInfo)
 }
 
 // Debug logs a message that is intended for use in a development
@@ -52,16 +54,16 @@ func (l *StderrLogger) Info(msg string) {
 // software
 // Arguments are handled in the manner of fmt.Println.
 func (l *StderrLogger) Debug(msg string) {
-	l.write(msg, levelDebug)
+	l.write(msg, LevelDebug)
 }
 
 // Log logs a message that might result a failure
 // Arguments are handled in the manner of fmt.Println.
 func (l *StderrLogger) Log(msg string) {
-	l.write(msg, levelDefault)
+	l.write(msg, LevelDefault)
 }
 
-func (l *StderrLogger) write(msg string, lvl logLevel) {
+func (l *StderrLogger) write(msg string, lvl Level) {
 	msg = lvl.Tag() + msg
 	log.Print(msg)
 }
@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultTextTemplate is the template used by TemplateFormatter when
+// none is provided
+const DefaultTextTemplate = "{time} {level} {tag} {msg} {fields}"
+
+// FieldsFormat represents the way the globals are printed by
+// TemplateFormatter
+type FieldsFormat int
+
+// All the formats supported by TemplateFormatter
+const (
+	// FieldsJSON prints the globals as a JSON object
+	FieldsJSON FieldsFormat = iota
+	// FieldsLogfmt prints the globals as key=value pairs
+	FieldsLogfmt
+)
+
+// placeholderRegexp matches the placeholders of a template
+var placeholderRegexp = regexp.MustCompile(`\{(\w+)\}`)
+
+// we make sure TemplateFormatter implements Formatter
+var _ Formatter = (*TemplateFormatter)(nil)
+
+// NewTemplateFormatter creates and returns a formatter that prints the
+// entries using the given template. The supported placeholders are:
+//
+//	{time}   the time of the entry, printed using TimeLayout
+//	{seq}    the sequence number of the entry
+//	{level}  the label of the level of the entry, from LevelLabels
+//	{tag}    the tags of the entry, joined with TagSeparator
+//	{msg}    the message of the entry
+//	{fields} the globals and fields of the entry, printed using FieldsFormat
+//
+// When a placeholder is empty, the space following it is removed, so
+// "{level}{tag} {msg}" prints "[ERROR]msg" for an entry without tags.
+// Returns an error if the template contains an unknown placeholder
+func NewTemplateFormatter(tmpl string) (*TemplateFormatter, error) {
+	if tmpl == "" {
+		tmpl = DefaultTextTemplate
+	}
+
+	segments := []templateSegment{}
+	last := 0
+	for _, loc := range placeholderRegexp.FindAllStringSubmatchIndex(tmpl, -1) {
+		name := tmpl[loc[2]:loc[3]]
+		switch name {
+		case "time", "seq", "level", "tag", "msg", "fields":
+		default:
+			return nil, errors.Errorf("unknown placeholder {%s}", name)
+		}
+		if loc[0] > last {
+			segments = append(segments, templateSegment{literal: tmpl[last:loc[0]]})
+		}
+		segments = append(segments, templateSegment{placeholder: name})
+		last = loc[1]
+	}
+	if last < len(tmpl) {
+		segments = append(segments, templateSegment{literal: tmpl[last:]})
+	}
+
+	return &TemplateFormatter{
+		TimeLayout: time.RFC3339,
+		MessagePolicy: MessagePolicy{
+			Multiline: MultilineEscape,
+		},
+		segments: segments,
+		now:      time.Now,
+	}, nil
+}
+
+// TemplateFormatter is a formatter that prints entries on a single line,
+// following a template. The new lines of the messages are escaped by
+// default.
+// The exported fields must not be changed once the formatter is in use
+type TemplateFormatter struct {
+	MessagePolicy
+
+	// LevelLabels contains the labels used for {level}.
+	// Level.String() is used for the levels that don't have a label
+	LevelLabels map[Level]string
+
+	// TagSeparator is the string used to join the tags of {tag}
+	TagSeparator string
+
+	// TimeLayout is the layout used to print {time}.
+	// Defaults to time.RFC3339
+	TimeLayout string
+
+	// FieldsFormat is the format used to print {fields}.
+	// Defaults to FieldsJSON
+	FieldsFormat FieldsFormat
+
+	segments []templateSegment
+	now      Clock // used for the entries that don't have a time
+}
+
+// templateSegment is either a literal string or a placeholder
+type templateSegment struct {
+	literal     string
+	placeholder string
+}
+
+// Format returns the text representation of an entry
+func (f *TemplateFormatter) Format(e *Entry) ([]byte, error) {
+	return f.formatMessage(e, f.format)
+}
+
+// format returns the text representation of an entry, once the message
+// policy has been applied
+func (f *TemplateFormatter) format(e *Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	skipSpace := false
+	for i, seg := range f.segments {
+		if seg.placeholder == "" {
+			lit := seg.literal
+			if skipSpace {
+				lit = strings.TrimPrefix(lit, " ")
+			}
+			buf.WriteString(lit)
+			skipSpace = false
+			continue
+		}
+
+		value, err := f.render(seg.placeholder, e)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(value)
+		skipSpace = value == ""
+
+		// an empty placeholder at the end of the template removes the
+		// space preceding it
+		if skipSpace && i == len(f.segments)-1 {
+			buf.Truncate(len(bytes.TrimSuffix(buf.Bytes(), []byte(" "))))
+		}
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// render returns the value of a placeholder
+func (f *TemplateFormatter) render(placeholder string, e *Entry) (string, error) {
+	switch placeholder {
+	case "time":
+		return entryTime(e, f.now).Format(f.TimeLayout), nil
+	case "seq":
+		if e.Seq == 0 {
+			return "", nil
+		}
+		return strconv.FormatUint(e.Seq, 10), nil
+	case "level":
+		if label, ok := f.LevelLabels[e.Level]; ok {
+			return label, nil
+		}
+		return e.Level.String(), nil
+	case "tag":
+		if e.Tags == nil {
+			return e.Tag, nil
+		}
+		return strings.Join(e.Tags, f.TagSeparator), nil
+	case "msg":
+		return e.Message, nil
+	case "fields":
+		return f.fields(e.Data())
+	}
+	return "", nil
+}
+
+// fields returns the text representation of the data of an entry
+func (f *TemplateFormatter) fields(globals map[string]interface{}) (string, error) {
+	if len(globals) == 0 {
+		return "", nil
+	}
+
+	if f.FieldsFormat != FieldsLogfmt {
+		return string(encodeData(globals)), nil
+	}
+
+	pairs := make([]string, 0, len(globals))
+	for _, k := range sortedKeys(globals) {
+		pairs = append(pairs, k+"="+logfmtValue(globals[k]))
+	}
+	return strings.Join(pairs, " "), nil
+}
+
+// logfmtValue returns the logfmt representation of a value, quoting it
+// if needed
+func logfmtValue(v interface{}) string {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
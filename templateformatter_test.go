@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2019, 5, 4, 10, 30, 0, 0, time.UTC)
+	newFormatter := func(tmpl string) *TemplateFormatter {
+		f, err := NewTemplateFormatter(tmpl)
+		require.NoError(t, err)
+		f.now = func() time.Time { return now }
+		return f
+	}
+	format := func(f Formatter, e *Entry) string {
+		data, err := f.Format(e)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("default template", func(t *testing.T) {
+		t.Parallel()
+		f := newFormatter("")
+
+		e := &Entry{
+			Level:   LevelError,
+			Message: "message",
+			Tags:    []string{"[app]", "[db]"},
+			Globals: map[string]interface{}{"b": 1, "a": "value"},
+		}
+		assert.Equal(t, `2019-05-04T10:30:00Z ERROR [app][db] message {"a":"value","b":1}`+"\n", format(f, e))
+
+		e = &Entry{Level: LevelInfo, Message: "message"}
+		assert.Equal(t, "2019-05-04T10:30:00Z INFO message\n", format(f, e))
+	})
+
+	t.Run("custom labels, separator and layout", func(t *testing.T) {
+		t.Parallel()
+		f := newFormatter("{time}|{level}|{tag}|{msg}")
+		f.LevelLabels = map[Level]string{LevelError: "E"}
+		f.TagSeparator = "."
+		f.TimeLayout = "15:04"
+
+		e := &Entry{Level: LevelError, Message: "a", Tags: []string{"app", "db"}}
+		assert.Equal(t, "10:30|E|app.db|a\n", format(f, e))
+
+		e = &Entry{Level: LevelInfo, Message: "b", Tag: "app"}
+		assert.Equal(t, "10:30|INFO|app|b\n", format(f, e), "Tag should be used when Tags is nil")
+	})
+
+	t.Run("sequence number", func(t *testing.T) {
+		t.Parallel()
+		f := newFormatter("#{seq} {msg}")
+
+		assert.Equal(t, "#42 a\n", format(f, &Entry{Seq: 42, Message: "a"}))
+		assert.Equal(t, "#b\n", format(f, &Entry{Message: "b"}), "no sequence number should be printed for entries without one")
+	})
+
+	t.Run("reproduces the default text format", func(t *testing.T) {
+		t.Parallel()
+		f := newFormatter("{level}{tag} {msg}")
+		f.LevelLabels = map[Level]string{
+			LevelDebug:   LevelDebug.Tag(),
+			LevelInfo:    LevelInfo.Tag(),
+			LevelError:   LevelError.Tag(),
+			LevelDefault: LevelDefault.Tag(),
+		}
+
+		var buf bytes.Buffer
+		m := NewManager()
+		require.NoError(t, m.Add(NewWriterLogger(&buf, f)))
+		m.Error("a", "b")
+		m.NewSubManager("[child]").Log("c")
+		assert.Equal(t, "[ERROR]a b\n[child] c\n", buf.String())
+	})
+
+	t.Run("logfmt fields", func(t *testing.T) {
+		t.Parallel()
+		f := newFormatter("{msg} {fields}")
+		f.FieldsFormat = FieldsLogfmt
+
+		e := &Entry{
+			Message: "message",
+			Globals: map[string]interface{}{
+				"user":  "john doe",
+				"count": 2,
+				"err":   errors.New("not found"),
+				"empty": "",
+			},
+		}
+		assert.Equal(t, `message count=2 empty="" err="not found" user="john doe"`+"\n", format(f, e))
+	})
+
+	t.Run("unknown placeholders", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewTemplateFormatter("{msg} {nope}")
+		assert.Error(t, err)
+	})
+}
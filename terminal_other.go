@@ -0,0 +1,10 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package logger
+
+// isTerminal returns whether fd is a terminal. Terminals are not
+// detected on this platform, so the output is never colored
+func isTerminal(fd uintptr) bool {
+	return false
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"io"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// we make sure WriterLogger implements EntryLogger and Flusher
+var (
+	_ EntryLogger = (*WriterLogger)(nil)
+	_ Flusher     = (*WriterLogger)(nil)
+)
+
+// NewWriterLogger creates and returns a logger that uses the given
+// formatter to write entries on w
+func NewWriterLogger(w io.Writer, f Formatter) Logger {
+	return &WriterLogger{
+		id:        "writer-logger:" + uuid.New().String(),
+		w:         w,
+		formatter: f,
+	}
+}
+
+// WriterLogger is a go-routine safe, non-buffered logger that writes
+// formatted entries on an io.Writer
+type WriterLogger struct {
+	mu        sync.Mutex
+	id        string
+	w         io.Writer
+	formatter Formatter
+	closed    bool
+}
+
+// ID returns the logger's unique ID
+func (l *WriterLogger) ID() string {
+	return l.id
+}
+
+// Close frees any resource allocated by the logger
+// the logger may not be reusable after being closed.
+// The writer is not closed
+func (l *WriterLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	return nil
+}
+
+// IsClosed returns wether the logger is closed or not
+func (l *WriterLogger) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// Flush flushes the writer if it implements Flusher, like bufio.Writer
+func (l *WriterLogger) Flush() error {
+	f, ok := l.w.(Flusher)
+	if !ok {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return f.Flush()
+}
+
+// LogEntry formats an entry and writes it on the writer
+func (l *WriterLogger) LogEntry(e *Entry) error {
+	data, err := l.formatter.Format(e)
+	if err != nil {
+		return errors.Wrap(err, "could not format the entry")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(data); err != nil {
+		return errors.Wrap(err, "could not write the entry")
+	}
+	return nil
+}
+
+// Error logs an error message
+// Arguments are handled in the manner of fmt.Println.
+func (l *WriterLogger) Error(msg string) {
+	WriteLevel(l, msg, LevelError)
+}
+
+// Info logs a message that may be helpful, but isn’t essential,
+// for troubleshooting
+// Arguments are handled in the manner of fmt.Println.
+func (l *WriterLogger) Info(msg string) {
+	WriteLevel(l, msg, LevelInfo)
+}
+
+// Debug logs a message that is intended for use in a development
+// environment while actively debugging your subsystem, not in shipping
+// software
+// Arguments are handled in the manner of fmt.Println.
+func (l *WriterLogger) Debug(msg string) {
+	WriteLevel(l, msg, LevelDebug)
+}
+
+// Log logs a message that might result a failure
+// Arguments are handled in the manner of fmt.Println.
+func (l *WriterLogger) Log(msg string) {
+	WriteLevel(l, msg, LevelDefault)
+}
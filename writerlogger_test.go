@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// levelFormatter is a formatter that only prints the level and the message
+type levelFormatter struct{}
+
+func (f levelFormatter) Format(e *Entry) ([]byte, error) {
+	return []byte(fmt.Sprintf("%s %s\n", e.Level, e.Message)), nil
+}
+
+func TestWriterLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IDs are unique", func(t *testing.T) {
+		t.Parallel()
+		l1 := NewWriterLogger(&bytes.Buffer{}, levelFormatter{})
+		l2 := NewWriterLogger(&bytes.Buffer{}, levelFormatter{})
+		assert.NotEqual(t, l1.ID(), l2.ID())
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+		l := NewWriterLogger(&bytes.Buffer{}, levelFormatter{})
+		require.False(t, l.IsClosed())
+		require.NoError(t, l.Close())
+		require.True(t, l.IsClosed())
+	})
+
+	t.Run("Flush flushes buffered writers", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		l := NewWriterLogger(w, levelFormatter{})
+
+		l.Info("a\n")
+		assert.Empty(t, buf.String())
+		require.NoError(t, l.(Flusher).Flush())
+		assert.Equal(t, "INFO a\n", buf.String())
+	})
+
+	t.Run("string methods", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		l := NewWriterLogger(&buf, levelFormatter{})
+
+		l.Error("a\n")
+		l.Info("b\n")
+		l.Debug("c\n")
+		l.Log("d\n")
+
+		assert.Equal(t, "ERROR a\nINFO b\nDEBUG c\nLOG d\n", buf.String())
+	})
+
+	t.Run("entries from a manager", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		m := NewManager()
+		require.NoError(t, m.Add(NewWriterLogger(&buf, levelFormatter{})))
+
+		m.Infof("%s %s", "a", "b")
+		assert.Equal(t, "INFO a b\n", buf.String())
+	})
+}